@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"preq/internal/cli/utils"
+	"preq/internal/clientutils"
+	"preq/internal/credentialutils"
+	"preq/internal/pkg/client"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage provider credentials",
+		Long: strings.TrimSpace(`
+Stores and checks the credentials of the repository providers. The credentials
+are stored with the git credential helpers, e.g. the OS keychain.`),
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "login [PROVIDER]",
+		Short: "Store provider credentials",
+		Args:  cobra.ExactArgs(1),
+		Run:   utils.RunCommandWrapper(runLoginCmd),
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "logout [PROVIDER]",
+		Short: "Remove stored provider credentials",
+		Long: strings.TrimSpace(`
+Removes the credential of the provider's host from the git credential helpers.
+git uses the same credential for the HTTPS remotes of the host, so git asks for
+it again on the next push or pull over HTTPS.`),
+		Args: cobra.ExactArgs(1),
+		Run:  utils.RunCommandWrapper(runLogoutCmd),
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status [PROVIDER]",
+		Short: "Show the credential status of the providers",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   utils.RunCommandWrapper(runStatusCmd),
+	})
+
+	return cmd
+}
+
+func runLoginCmd(cmd *cobra.Command, args []string) error {
+	cmdArgs, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	config, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	credential := &credentialutils.Credential{
+		Username: credentialutils.GetUsername(cmdArgs.Provider, config),
+	}
+
+	if credential.Username == "" {
+		credential.Username, err = prompt(reader, "Username: ")
+		if err != nil {
+			return err
+		}
+	}
+
+	key := credentialutils.SecretKey(cmdArgs.Provider)
+	credential.Secret, err = promptSecret(reader, strings.ToUpper(key[:1])+key[1:]+": ")
+	if err != nil {
+		return err
+	}
+	if credential.Secret == "" {
+		return fmt.Errorf("missing %s", key)
+	}
+
+	// verify overrides the configured credential, so the preceding source is
+	// looked up first
+	preceding := credentialutils.PrecedingSource(cmdArgs.Provider, config)
+
+	user, err := verify(cmdArgs.Provider, config, credential)
+	if err != nil {
+		return fmt.Errorf("could not verify the credentials: %w", err)
+	}
+
+	err = credentialutils.Store(cmdArgs.Provider, config, credential)
+	if err != nil {
+		return fmt.Errorf("could not store the credentials: %w", err)
+	}
+
+	fmt.Printf("Logged in to %s as %s\n", cmdArgs.Provider, displayName(user))
+	if preceding != "" {
+		fmt.Fprintf(
+			os.Stderr,
+			"Warning: the credential from the %s takes precedence over the stored one, remove it to use the stored credential\n",
+			preceding,
+		)
+	}
+
+	return nil
+}
+
+func runLogoutCmd(cmd *cobra.Command, args []string) error {
+	cmdArgs, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	config, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	err = credentialutils.Erase(cmdArgs.Provider, config)
+	if err != nil {
+		return fmt.Errorf("could not remove the credentials: %w", err)
+	}
+
+	fmt.Printf("Logged out of %s\n", cmdArgs.Provider)
+
+	return nil
+}
+
+func runStatusCmd(cmd *cobra.Command, args []string) error {
+	cmdArgs, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	config, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	providers := client.RepositoryProviders()
+	if cmdArgs.Provider != "" {
+		providers = []client.RepositoryProvider{cmdArgs.Provider}
+	}
+
+	var statusErr error
+	for _, p := range providers {
+		credential, err := credentialutils.Resolve(p, config)
+		if err != nil {
+			fmt.Printf("%s: not logged in\n", p)
+			statusErr = err
+			continue
+		}
+
+		note := ""
+		if credential.Source != credentialutils.Source_GIT_CREDENTIAL && credentialutils.IsStored(p, config) {
+			note = ", the credential stored with preq auth login is not used"
+		}
+
+		user, err := verify(p, config, credential)
+		if err != nil {
+			fmt.Printf("%s: invalid credentials from %s (%v)%s\n", p, credential.Source, err, note)
+			statusErr = err
+			continue
+		}
+
+		fmt.Printf("%s: logged in as %s using %s%s\n", p, displayName(user), credential.Source, note)
+	}
+
+	// Only a single requested provider is an error, the rest are optional
+	if cmdArgs.Provider != "" {
+		return statusErr
+	}
+
+	return nil
+}
+
+// verify checks the credential by requesting the authenticated user
+func verify(
+	provider client.RepositoryProvider,
+	config *viper.Viper,
+	credential *credentialutils.Credential,
+) (*client.User, error) {
+	config.Set(fmt.Sprintf("%s.username", provider), credential.Username)
+	config.Set(fmt.Sprintf("%s.%s", provider, credentialutils.SecretKey(provider)), credential.Secret)
+	config.Set(fmt.Sprintf("%s.passwordCommand", provider), "")
+
+	c, err := clientutils.ClientFactory{}.NewClient(provider, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetCurrentUser()
+}
+
+func displayName(u *client.User) string {
+	if u.Username != "" {
+		return u.Username
+	}
+
+	return u.ID
+}
+
+func prompt(reader *bufio.Reader, label string) (string, error) {
+	fmt.Print(label)
+	line, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// promptSecret reads the secret without echoing it, piped input is read
+// as is so the secret can be passed from a password manager
+func promptSecret(reader *bufio.Reader, label string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return prompt(reader, "")
+	}
+
+	fmt.Print(label)
+	secret, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(secret)), nil
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"fmt"
+	"preq/internal/cli/paramutils"
+	"preq/internal/configutils"
+	"preq/internal/pkg/client"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type cmdArgs struct {
+	Provider client.RepositoryProvider
+}
+
+func parseArgs(args []string) (*cmdArgs, error) {
+	if len(args) == 0 {
+		return &cmdArgs{}, nil
+	}
+
+	provider := client.RepositoryProvider(args[0])
+	if !provider.IsValid() {
+		return nil, fmt.Errorf("unknown provider '%s', expected one of %v", args[0], client.RepositoryProviders())
+	}
+
+	return &cmdArgs{Provider: provider}, nil
+}
+
+// loadConfig loads the configuration of the current repository, falling back
+// to the global configuration outside of a repository
+func loadConfig(cmd *cobra.Command) (*viper.Viper, error) {
+	path, err := paramutils.GetRepoPath(cmd.Flags())
+	if err == nil {
+		return configutils.LoadConfigForPath(path)
+	}
+
+	config, err := configutils.DefaultConfig()
+	if err != nil {
+		return viper.New(), nil
+	}
+
+	return config, nil
+}
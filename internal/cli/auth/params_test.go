@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseArgs(t *testing.T) {
+	args, err := parseArgs([]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, &cmdArgs{}, args)
+
+	args, err = parseArgs([]string{"gitlab"})
+	assert.NoError(t, err)
+	assert.Equal(t, &cmdArgs{Provider: client.RepositoryProviderEnum.GITLAB}, args)
+
+	_, err = parseArgs([]string{"svn"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package checkout
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"preq/internal/cli/paramutils"
+	"preq/internal/cli/utils"
+	"preq/internal/gitutils"
+	"preq/internal/persistance"
+	"preq/internal/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "checkout ID",
+		Aliases: []string{"co"},
+		Short:   "Check out a pull request locally",
+		Long:    `Fetches the source branch of a pull request, including the ones from forks, and switches to a local branch tracking it`,
+		Args:    cobra.ExactArgs(1),
+		Run:     utils.RunCommandWrapper(runCmd),
+	}
+
+	cmd.Flags().String("branch", "", "Name of the local branch, defaults to pr/ID")
+	cmd.Flags().Bool("stash", false, "Stash the uncommitted changes before switching")
+
+	return cmd
+}
+
+func runCmd(cmd *cobra.Command, args []string) error {
+	cmdArgs := parseArgs(args)
+	params := &checkoutCmdParams{}
+	fillFlagCheckoutCmdParams(paramutils.NewFlagRepo(cmd.Flags()), params)
+
+	cl, repoParams, err := paramutils.GetClientAndRepoParams(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	utils.SafelyWriteVisitToState(cmd.Flags(), repoParams)
+
+	repo := &client.Repository{
+		Provider: repoParams.Provider,
+		Name:     repoParams.Name,
+	}
+
+	path, err := repoPath(repo)
+	if err != nil {
+		return err
+	}
+
+	return execute(cl, cmdArgs, params, repo, path, os.Stdout)
+}
+
+var getRepoInfo = func(repo *client.Repository) (*persistance.PersistanceRepoInfo, error) {
+	return persistance.GetDefault().GetInfo(repo.Name, string(repo.Provider))
+}
+
+// repoPath returns the local clone of the repository known from the
+// previous visits
+func repoPath(repo *client.Repository) (string, error) {
+	info, err := getRepoInfo(repo)
+	if err != nil || info.Path == "" {
+		return "", fmt.Errorf(
+			"no local clone of %s is known, run preq in the repository first",
+			repo.Name,
+		)
+	}
+
+	return info.Path, nil
+}
+
+var checkoutPullRequest = gitutils.CheckoutPullRequest
+
+func execute(
+	c client.Client,
+	args *cmdArgs,
+	params *checkoutCmdParams,
+	repo *client.Repository,
+	path string,
+	w io.Writer,
+) error {
+	pr, err := c.GetPullRequestInfo(&client.ApproveOptions{
+		Repository: repo,
+		ID:         args.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	result, err := checkoutPullRequest(path, repo, pr, &gitutils.CheckoutOptions{
+		Branch: params.Branch,
+		Stash:  params.Stash,
+	})
+	if result != nil && result.Stashed {
+		fmt.Fprintln(w, "Stashed the uncommitted changes, restore them with `git stash pop`")
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Switched to branch '%s' of pull request #%s %s\n", result.Branch, pr.ID, pr.Title)
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package checkout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"preq/internal/gitutils"
+	"preq/internal/persistance"
+	"preq/internal/pkg/client"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPullRequestClient struct {
+	client.MockClient
+	pr *client.PullRequest
+}
+
+func (c *mockPullRequestClient) GetPullRequestInfo(o *client.ApproveOptions) (*client.PullRequest, error) {
+	return c.pr, c.ErrorValue
+}
+
+func Test_execute(t *testing.T) {
+	repo := &client.Repository{Provider: client.RepositoryProviderEnum.GITHUB, Name: "owner/repo"}
+	pr := &client.PullRequest{ID: "7", Title: "Feature"}
+
+	defer func(f func(string, *client.Repository, *client.PullRequest, *gitutils.CheckoutOptions) (*gitutils.CheckoutResult, error)) {
+		checkoutPullRequest = f
+	}(checkoutPullRequest)
+
+	t.Run("checks out the pull request in the repository path", func(t *testing.T) {
+		var (
+			path    string
+			options *gitutils.CheckoutOptions
+		)
+		checkoutPullRequest = func(p string, r *client.Repository, v *client.PullRequest, o *gitutils.CheckoutOptions) (*gitutils.CheckoutResult, error) {
+			path, options = p, o
+			return &gitutils.CheckoutResult{Branch: "pr/7", Stashed: o.Stash}, nil
+		}
+
+		w := &bytes.Buffer{}
+		err := execute(&mockPullRequestClient{pr: pr}, &cmdArgs{ID: "7"}, &checkoutCmdParams{Stash: true}, repo, "/src/repo", w)
+		assert.NoError(t, err)
+		assert.Equal(t, "/src/repo", path)
+		assert.True(t, options.Stash)
+		assert.Contains(t, w.String(), "Stashed the uncommitted changes")
+		assert.Contains(t, w.String(), "Switched to branch 'pr/7' of pull request #7 Feature")
+	})
+
+	t.Run("fails when the pull request cannot be loaded", func(t *testing.T) {
+		vErr := errors.New("not found")
+		err := execute(&mockPullRequestClient{MockClient: client.MockClient{ErrorValue: vErr}}, &cmdArgs{ID: "7"}, &checkoutCmdParams{}, repo, "/src/repo", &bytes.Buffer{})
+		assert.ErrorIs(t, err, vErr)
+	})
+
+	t.Run("returns the dirty worktree error", func(t *testing.T) {
+		checkoutPullRequest = func(string, *client.Repository, *client.PullRequest, *gitutils.CheckoutOptions) (*gitutils.CheckoutResult, error) {
+			return nil, gitutils.ErrDirtyWorktree
+		}
+
+		err := execute(&mockPullRequestClient{pr: pr}, &cmdArgs{ID: "7"}, &checkoutCmdParams{}, repo, "/src/repo", &bytes.Buffer{})
+		assert.ErrorIs(t, err, gitutils.ErrDirtyWorktree)
+	})
+}
+
+func Test_repoPath(t *testing.T) {
+	repo := &client.Repository{Provider: client.RepositoryProviderEnum.GITHUB, Name: "owner/repo"}
+	defer func(f func(*client.Repository) (*persistance.PersistanceRepoInfo, error)) { getRepoInfo = f }(getRepoInfo)
+
+	getRepoInfo = func(*client.Repository) (*persistance.PersistanceRepoInfo, error) {
+		return &persistance.PersistanceRepoInfo{Path: "/src/repo"}, nil
+	}
+	path, err := repoPath(repo)
+	assert.NoError(t, err)
+	assert.Equal(t, "/src/repo", path)
+
+	getRepoInfo = func(*client.Repository) (*persistance.PersistanceRepoInfo, error) {
+		return &persistance.PersistanceRepoInfo{}, nil
+	}
+	_, err = repoPath(repo)
+	assert.Error(t, err)
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	args = append([]string{"-c", "user.name=preq", "-c", "user.email=preq@example.com"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %s", strings.Join(args, " "), output)
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+func Test_execute_bitbucketFork(t *testing.T) {
+	root := t.TempDir()
+	upstream := filepath.Join(root, "owner", "repo")
+	fork := filepath.Join(root, "fork", "repo")
+	clone := filepath.Join(root, "clone")
+
+	runTestGit(t, root, "init", "-q", "-b", "main", upstream)
+	runTestGit(t, upstream, "commit", "-q", "--allow-empty", "-m", "initial")
+	runTestGit(t, root, "clone", "-q", upstream, fork)
+	runTestGit(t, fork, "switch", "-q", "-c", "feature")
+	runTestGit(t, fork, "commit", "-q", "--allow-empty", "-m", "feature")
+	runTestGit(t, root, "clone", "-q", upstream, clone)
+
+	// The fork's Bitbucket URL is redirected to the local fork
+	runTestGit(t, clone, "remote", "set-url", "origin", "https://bitbucket.org/owner/repo.git")
+	runTestGit(t, clone, "config", fmt.Sprintf("url.%s.insteadOf", fork), "https://bitbucket.org/fork/repo.git")
+
+	repo := &client.Repository{Provider: client.RepositoryProviderEnum.BITBUCKET, Name: "owner/repo"}
+	pr := &client.PullRequest{
+		ID:     "7",
+		Title:  "Feature",
+		Source: client.PullRequestBranch{Name: "feature", Repository: "fork/repo"},
+	}
+
+	w := &bytes.Buffer{}
+	err := execute(&mockPullRequestClient{pr: pr}, &cmdArgs{ID: "7"}, &checkoutCmdParams{}, repo, clone, w)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, runTestGit(t, fork, "rev-parse", "feature"), runTestGit(t, clone, "rev-parse", "HEAD"))
+	assert.Equal(t, "https://bitbucket.org/fork/repo.git", runTestGit(t, clone, "config", "branch.pr/7.remote"))
+	assert.Contains(t, w.String(), "Switched to branch 'pr/7' of pull request #7 Feature")
+}
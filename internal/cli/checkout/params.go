@@ -0,0 +1,23 @@
+package checkout
+
+import (
+	"preq/internal/cli/paramutils"
+)
+
+type checkoutCmdParams struct {
+	Branch string
+	Stash  bool
+}
+
+type cmdArgs struct {
+	ID string
+}
+
+func parseArgs(args []string) *cmdArgs {
+	return &cmdArgs{ID: paramutils.ParseIDArg(args)}
+}
+
+func fillFlagCheckoutCmdParams(flags paramutils.FlagRepo, params *checkoutCmdParams) {
+	params.Branch = flags.GetStringOrDefault("branch", "")
+	params.Stash = flags.GetBoolOrDefault("stash", false)
+}
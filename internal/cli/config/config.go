@@ -0,0 +1,279 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"preq/internal/cli/paramutils"
+	"preq/internal/cli/utils"
+	"preq/internal/configutils"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var ErrKeyNotFound = errors.New("key not found")
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect and edit the configuration",
+		Long: strings.TrimSpace(`
+Inspects and edits the global configuration file and the .preqcfg file of the
+repository in the current directory. The values of the repository configuration
+override the global ones.`),
+	}
+
+	getCmd := &cobra.Command{
+		Use:   "get [KEY]",
+		Short: "Print the value of a key",
+		Args:  cobra.ExactArgs(1),
+		Run:   utils.RunCommandWrapper(runGetCmd),
+	}
+	getCmd.Flags().Bool("show-origin", false, "Print the file the value comes from")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Print all the configured values",
+		Args:  cobra.NoArgs,
+		Run:   utils.RunCommandWrapper(runListCmd),
+	}
+	listCmd.Flags().Bool("show-origin", false, "Print the file each value comes from")
+
+	setCmd := &cobra.Command{
+		Use:   "set [KEY] [VALUE...]",
+		Short: "Set the value of a key",
+		Long:  "Sets the value of a key in the global configuration, or in the repository configuration with --local. List values can be given as separate arguments or separated by commas. The file is rewritten from its values, its comments are lost and its keys are sorted.",
+		Args:  cobra.MinimumNArgs(2),
+		Run:   utils.RunCommandWrapper(runSetCmd),
+	}
+	setCmd.Flags().Bool("local", false, "Write to the repository configuration")
+
+	unsetCmd := &cobra.Command{
+		Use:   "unset [KEY]",
+		Short: "Remove a key",
+		Long:  "Removes a key from the global configuration, or from the repository configuration with --local. The file is rewritten from its values, its comments are lost and its keys are sorted.",
+		Args:  cobra.ExactArgs(1),
+		Run:   utils.RunCommandWrapper(runUnsetCmd),
+	}
+	unsetCmd.Flags().Bool("local", false, "Remove from the repository configuration")
+
+	cmd.AddCommand(getCmd, listCmd, setCmd, unsetCmd)
+	cmd.AddCommand(&cobra.Command{
+		Use:   "path",
+		Short: "Print the paths of the configuration files",
+		Args:  cobra.NoArgs,
+		Run:   utils.RunCommandWrapper(runPathCmd),
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "validate",
+		Short: "Check the configuration for problems",
+		Long:  "Reports unknown keys, bad alias definitions and missing credentials of the configured providers and of the repository in the current directory.",
+		Args:  cobra.NoArgs,
+		Run:   utils.RunCommandWrapper(runValidateCmd),
+	})
+
+	return cmd
+}
+
+type configFiles struct {
+	global   *configutils.ConfigFile
+	local    *configutils.ConfigFile
+	repoPath string
+}
+
+// layers returns the files in the order of precedence, lowest first
+func (f *configFiles) layers() []*configutils.ConfigFile {
+	if f.local == nil {
+		return []*configutils.ConfigFile{f.global}
+	}
+
+	return []*configutils.ConfigFile{f.global, f.local}
+}
+
+// loadFiles reads the global configuration and the configuration of the
+// repository in the current directory, if there is one
+func loadFiles(cmd *cobra.Command) (*configFiles, error) {
+	globalPath, err := configutils.GlobalConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	files := &configFiles{}
+	files.global, err = configutils.ReadConfigFile(globalPath)
+	if err != nil {
+		return nil, err
+	}
+
+	repoPath, err := paramutils.GetRepoPath(cmd.Flags())
+	if err != nil {
+		return files, nil
+	}
+
+	files.repoPath = repoPath
+	files.local, err = configutils.ReadConfigFile(configutils.LocalConfigPath(repoPath))
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+type configValue struct {
+	Key    string
+	Value  interface{}
+	Origin string
+}
+
+// collectValues merges the values of the files, the latter files override
+// the values of the former
+func collectValues(files ...*configutils.ConfigFile) []*configValue {
+	values := map[string]*configValue{}
+	for _, f := range files {
+		for _, k := range f.Keys() {
+			v, _ := f.Get(k)
+			values[strings.ToLower(k)] = &configValue{Key: k, Value: v, Origin: f.Path}
+		}
+	}
+
+	result := make([]*configValue, 0, len(values))
+	for _, v := range values {
+		result = append(result, v)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Key) < strings.ToLower(result[j].Key)
+	})
+
+	return result
+}
+
+func lookupValue(key string, files ...*configutils.ConfigFile) (*configValue, error) {
+	for i := len(files) - 1; i >= 0; i-- {
+		v, ok := files[i].Get(key)
+		if !ok {
+			continue
+		}
+
+		if _, isSection := v.(map[string]interface{}); isSection {
+			return nil, fmt.Errorf("'%s' is a section, use the list command to show its values", key)
+		}
+
+		return &configValue{Key: key, Value: v, Origin: files[i].Path}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+}
+
+func runGetCmd(cmd *cobra.Command, args []string) error {
+	files, err := loadFiles(cmd)
+	if err != nil {
+		return err
+	}
+
+	v, err := lookupValue(args[0], files.layers()...)
+	if err != nil {
+		return err
+	}
+
+	showOrigin, _ := cmd.Flags().GetBool("show-origin")
+	if showOrigin {
+		fmt.Printf("%s\t", v.Origin)
+	}
+	fmt.Println(configutils.FormatValue(v.Value))
+
+	return nil
+}
+
+func runListCmd(cmd *cobra.Command, args []string) error {
+	files, err := loadFiles(cmd)
+	if err != nil {
+		return err
+	}
+
+	showOrigin, _ := cmd.Flags().GetBool("show-origin")
+	for _, v := range collectValues(files.layers()...) {
+		if showOrigin {
+			fmt.Printf("%s\t", v.Origin)
+		}
+		fmt.Printf("%s=%s\n", v.Key, configutils.FormatValue(v.Value))
+	}
+
+	return nil
+}
+
+// targetFile returns the file the set and unset commands write to
+func targetFile(cmd *cobra.Command, files *configFiles) (*configutils.ConfigFile, error) {
+	local, _ := cmd.Flags().GetBool("local")
+	if !local {
+		return files.global, nil
+	}
+
+	if files.local == nil {
+		return nil, errors.New("the current directory is not a git repository")
+	}
+
+	return files.local, nil
+}
+
+func runSetCmd(cmd *cobra.Command, args []string) error {
+	key, keyType, ok := configutils.LookupKey(args[0])
+	if !ok {
+		return fmt.Errorf("unknown key '%s', supported keys are:\n  %s", args[0], strings.Join(configutils.KnownKeys(), "\n  "))
+	}
+
+	value, err := configutils.ParseValue(keyType, args[1:])
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	files, err := loadFiles(cmd)
+	if err != nil {
+		return err
+	}
+
+	f, err := targetFile(cmd, files)
+	if err != nil {
+		return err
+	}
+
+	f.Set(key, value)
+
+	return f.Write()
+}
+
+func runUnsetCmd(cmd *cobra.Command, args []string) error {
+	files, err := loadFiles(cmd)
+	if err != nil {
+		return err
+	}
+
+	f, err := targetFile(cmd, files)
+	if err != nil {
+		return err
+	}
+
+	if !f.Unset(args[0]) {
+		return fmt.Errorf("%w in %s: %s", ErrKeyNotFound, f.Path, args[0])
+	}
+
+	return f.Write()
+}
+
+func runPathCmd(cmd *cobra.Command, args []string) error {
+	files, err := loadFiles(cmd)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files.layers() {
+		status := ""
+		if _, err := os.Stat(f.Path); err != nil {
+			status = " (missing)"
+		}
+
+		fmt.Printf("%s%s\n", f.Path, status)
+	}
+
+	return nil
+}
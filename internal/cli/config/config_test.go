@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"preq/internal/configutils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readFile(t *testing.T, name, content string) *configutils.ConfigFile {
+	path := filepath.Join(t.TempDir(), name)
+	os.WriteFile(path, []byte(content), 0o600)
+
+	f, err := configutils.ReadConfigFile(path)
+	assert.NoError(t, err)
+
+	return f
+}
+
+func Test_collectValues(t *testing.T) {
+	global := readFile(t, "config.toml", "reviewers = [\"a\"]\n[github]\nbaseUrl = \"x\"\n")
+	local := readFile(t, ".preqcfg", "reviewers: [b]\n")
+
+	values := collectValues(global, local)
+	assert.Equal(t, []*configValue{
+		{Key: "github.baseUrl", Value: "x", Origin: global.Path},
+		{Key: "reviewers", Value: []interface{}{"b"}, Origin: local.Path},
+	}, values)
+}
+
+func Test_lookupValue(t *testing.T) {
+	global := readFile(t, "config.toml", "[github]\nbaseUrl = \"x\"\n")
+	local := readFile(t, ".preqcfg", "")
+
+	v, err := lookupValue("github.baseurl", global, local)
+	assert.NoError(t, err)
+	assert.Equal(t, global.Path, v.Origin)
+
+	_, err = lookupValue("github", global, local)
+	assert.Error(t, err)
+
+	_, err = lookupValue("gitlab.token", global, local)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"preq/internal/cli/paramutils"
+	"preq/internal/configutils"
+	"preq/internal/credentialutils"
+	"preq/internal/gitutils"
+	"preq/internal/pkg/client"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func runValidateCmd(cmd *cobra.Command, args []string) error {
+	files, err := loadFiles(cmd)
+	if err != nil {
+		return err
+	}
+
+	problems := []string{}
+	for _, f := range files.layers() {
+		problems = append(problems, configutils.ValidateKeys(f)...)
+	}
+
+	config, err := configutils.Viper(files.layers()...)
+	if err != nil {
+		return err
+	}
+
+	aliases := paramutils.GetProviderAliases(config)
+	problems = append(problems, configutils.ValidateAliases(aliases)...)
+
+	providers := configuredProviders(config)
+	if files.repoPath != "" {
+		p, err := repositoryProvider(files.repoPath, aliases)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf(
+				"the provider of the repository at %s is unknown, add its host to the aliases: %v",
+				files.repoPath, err,
+			))
+		} else if !containsProvider(providers, p) {
+			providers = append(providers, p)
+		}
+	}
+
+	for _, p := range providers {
+		problems = append(problems, validateCredentials(p, config)...)
+	}
+
+	if len(problems) == 0 {
+		fmt.Println("No problems found")
+		return nil
+	}
+
+	for _, p := range problems {
+		fmt.Printf("- %s\n", p)
+	}
+
+	return fmt.Errorf("found %d problem(s)", len(problems))
+}
+
+// configuredProviders returns the providers with a configuration section
+func configuredProviders(config *viper.Viper) []client.RepositoryProvider {
+	providers := []client.RepositoryProvider{}
+	for _, p := range client.RepositoryProviders() {
+		if config.IsSet(string(p)) {
+			providers = append(providers, p)
+		}
+	}
+
+	return providers
+}
+
+func repositoryProvider(
+	path string,
+	aliases map[client.RepositoryProvider][]string,
+) (client.RepositoryProvider, error) {
+	git, err := gitutils.GetRepo(path)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := git.GetRemoteInfo(aliases)
+	if err != nil {
+		return "", err
+	}
+
+	return info.Provider, nil
+}
+
+func containsProvider(providers []client.RepositoryProvider, p client.RepositoryProvider) bool {
+	for _, v := range providers {
+		if v == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateCredentials reports the settings the provider's client
+// cannot be created without
+func validateCredentials(p client.RepositoryProvider, config *viper.Viper) []string {
+	problems := []string{}
+	if p == client.RepositoryProviderEnum.BITBUCKET_SERVER && config.GetString("bitbucketserver.baseUrl") == "" {
+		problems = append(problems, "missing bitbucketserver.baseUrl")
+	}
+
+	credential, err := credentialutils.Resolve(p, config)
+	if err != nil {
+		return append(problems, fmt.Sprintf("%s: %v", p, err))
+	}
+
+	needsUsername := p == client.RepositoryProviderEnum.BITBUCKET ||
+		p == client.RepositoryProviderEnum.GITHUB
+	if needsUsername && credential.Username == "" {
+		problems = append(problems, fmt.Sprintf("missing %s.username", p))
+	}
+
+	return problems
+}
@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"preq/internal/cli/paramutils"
 	"preq/internal/cli/utils"
+	"preq/internal/clientutils"
+	"preq/internal/configutils"
 	"preq/internal/domain/pullrequest"
+	"preq/internal/editorutils"
 	"preq/internal/gitutils"
 	"preq/internal/pkg/client"
 
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -32,10 +36,15 @@ func setUpFlags(cmd *cobra.Command) {
 		StringP("source", "s", "", "destination branch of your pull request (default checked out branch)")
 	cmd.Flags().
 		StringP("title", "t", "", "the title of the pull request (default last commit message)")
-	// TODO: Open default editor for description?
 	cmd.Flags().String("description", "", "the description of the pull request")
+	cmd.Flags().
+		BoolP("edit", "e", false, "edit the title and the description in your editor ($VISUAL or $EDITOR)")
 	cmd.Flags().Bool("close", true, "do not close source branch")
 	cmd.Flags().Bool("draft", false, "mark the pull request as draft")
+	cmd.Flags().
+		StringSlice("reviewer", []string{}, "add a reviewer by name or nickname (can be repeated)")
+	cmd.Flags().
+		Bool("no-default-reviewers", false, "do not add the repository's default reviewers")
 }
 
 func runCmd(cmd *cobra.Command, args []string) error {
@@ -53,9 +62,27 @@ func runCmd(cmd *cobra.Command, args []string) error {
 
 	fillInParamsFromFlags(&flags, params)
 	fillInParamsFromRepo(c, params)
-	// TODO: Also add fillInParamsFromConfig()?
+
+	path, err := paramutils.GetRepoPath(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	config, err := configutils.LoadConfigForPath(path)
+	if err != nil {
+		return err
+	}
+
+	fillInParamsFromConfig(config, params)
 	fillInDefaultParams(params)
 
+	if params.Edit {
+		err = editParams(c, path, params)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = params.Validate()
 	if err != nil {
 		return err
@@ -71,6 +98,29 @@ func runCmd(cmd *cobra.Command, args []string) error {
 	return execute(cl, params)
 }
 
+func editParams(
+	git gitutils.GitUtilsClient,
+	path string,
+	params *createCmdParams,
+) error {
+	commits, err := git.GetCommitMessages(params.Source, params.Destination)
+	if err != nil {
+		log.Warn().Err(err).Msg("unable to read the commit log")
+	}
+
+	content, err := editorutils.Edit(
+		buildEditorTemplate(params, commits, readPullRequestTemplate(path)),
+		"PULLREQ_EDITMSG-*.md",
+	)
+	if err != nil {
+		return err
+	}
+
+	params.Title, params.Description = parseEditorContent(content)
+
+	return nil
+}
+
 type creatorAdapter struct {
 	Client client.Client
 }
@@ -79,12 +129,16 @@ func (ca *creatorAdapter) Create(
 	o *pullrequest.CreateOptions,
 ) (*pullrequest.Entity, error) {
 	cpro := &client.CreatePullRequestOptions{
-		Repository:  o.Repository,
-		CloseBranch: o.CloseBranch,
-		Destination: o.Destination,
-		Source:      o.Source,
-		Title:       o.Title,
-		Draft:       o.Draft,
+		Repository:           o.Repository,
+		CloseBranch:          o.CloseBranch,
+		Destination:          o.Destination,
+		Source:               o.Source,
+		Title:                o.Title,
+		Description:          o.Description,
+		Draft:                o.Draft,
+		Reviewers:            o.Reviewers,
+		SkipDefaultReviewers: o.SkipDefaultReviewers,
+		Author:               o.Author,
 	}
 
 	pr, err := ca.Client.CreatePullRequest(cpro)
@@ -102,19 +156,38 @@ func (ca *creatorAdapter) Create(
 }
 
 func execute(c client.Client, params *createCmdParams) error {
+	repo := &client.Repository{
+		Provider: params.Repository.Provider,
+		Name:     params.Repository.Name,
+	}
+
+	reviewers, err := clientutils.ResolveReviewers(c, repo, params.Reviewers)
+	if err != nil {
+		return err
+	}
+
+	author := ""
+	user, err := c.GetCurrentUser()
+	if err != nil {
+		log.Warn().Err(err).Msg("unable to resolve the current user")
+	} else if user != nil {
+		author = user.ID
+	}
+
 	ca := &creatorAdapter{Client: c}
 
 	service := pullrequest.NewCreateService(ca)
 	pr, err := service.Create(&pullrequest.CreateOptions{
-		Repository: &client.Repository{
-			Provider: params.Repository.Provider,
-			Name:     params.Repository.Name,
-		},
-		CloseBranch: params.CloseBranch,
-		Title:       params.Title,
-		Source:      params.Source,
-		Destination: params.Destination,
-		Draft:       params.Draft,
+		Repository:           repo,
+		CloseBranch:          params.CloseBranch,
+		Title:                params.Title,
+		Description:          params.Description,
+		Source:               params.Source,
+		Destination:          params.Destination,
+		Draft:                params.Draft,
+		Reviewers:            reviewers,
+		SkipDefaultReviewers: params.NoDefaultReviewers,
+		Author:               author,
 	})
 	if err != nil {
 		return err
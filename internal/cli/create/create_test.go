@@ -1,5 +1,43 @@
 package cmdcreate
 
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseEditorContent(t *testing.T) {
+	t.Run("splits the title and the description", func(t *testing.T) {
+		title, description := parseEditorContent("\nTitle\n\nFirst line\nSecond line\n")
+		assert.Equal(t, "Title", title)
+		assert.Equal(t, "First line\nSecond line", description)
+	})
+
+	t.Run("returns an empty description for a single line", func(t *testing.T) {
+		title, description := parseEditorContent("Title\n")
+		assert.Equal(t, "Title", title)
+		assert.Equal(t, "", description)
+	})
+}
+
+func Test_appendUnique(t *testing.T) {
+	t.Run("skips existing values", func(t *testing.T) {
+		v := appendUnique([]string{"a"}, "b", "a", "b")
+		assert.Equal(t, []string{"a", "b"}, v)
+	})
+}
+
+func Test_buildEditorTemplate(t *testing.T) {
+	t.Run("adds the template and the commit subjects", func(t *testing.T) {
+		v := buildEditorTemplate(
+			&createCmdParams{Title: "Title"},
+			[]string{"second\n\nbody", "first"},
+			"## Changes",
+		)
+		assert.Equal(t, "Title\n\n## Changes\n\n- second\n- first\n", v)
+	})
+}
+
 // func Test_getRepo(t *testing.T) {
 // 	type args struct {
 // 		cmd *cobra.Command
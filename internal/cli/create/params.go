@@ -2,18 +2,40 @@ package cmdcreate
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"preq/internal/cli/paramutils"
 	"preq/internal/errcodes"
 	"preq/internal/gitutils"
+	"strings"
+
+	"github.com/spf13/viper"
 )
 
+// pullRequestTemplatePaths are the locations of the pull request templates
+// recognized by the providers, relative to the repository root
+var pullRequestTemplatePaths = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+	".gitlab/merge_request_templates/Default.md",
+}
+
 type createCmdParams struct {
 	Repository  paramutils.RepositoryParams
 	Source      string
 	Destination string
 	Title       string
+	Description string
 	CloseBranch bool
 	Draft       bool
+	Edit        bool
+	// Reviewers are names or nicknames which are resolved by the client
+	Reviewers          []string
+	NoDefaultReviewers bool
 }
 
 func (params *createCmdParams) Validate() error {
@@ -56,7 +78,23 @@ func fillInParamsFromFlags(flags paramutils.FlagRepo, params *createCmdParams) {
 		params.Title = flags.GetStringOrDefault("title", params.Title)
 	}
 
+	if params.Description == "" {
+		params.Description = flags.GetStringOrDefault(
+			"description",
+			params.Description,
+		)
+	}
+
+	params.Reviewers = appendUnique(
+		params.Reviewers,
+		flags.GetStringSliceOrDefault("reviewer", []string{})...,
+	)
+	params.NoDefaultReviewers = flags.GetBoolOrDefault(
+		"no-default-reviewers",
+		params.NoDefaultReviewers,
+	)
 	params.CloseBranch = flags.GetBoolOrDefault("close", params.CloseBranch)
+	params.Edit = flags.GetBoolOrDefault("edit", params.Edit)
 	params.Draft = flags.GetBoolOrDefault("draft", params.Draft)
 }
 
@@ -82,6 +120,31 @@ func fillInParamsFromRepo(
 	}
 }
 
+func fillInParamsFromConfig(config *viper.Viper, params *createCmdParams) {
+	params.Reviewers = appendUnique(
+		params.Reviewers,
+		config.GetStringSlice("reviewers")...,
+	)
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		exists := false
+		for _, l := range list {
+			if l == v {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
 func fillInDefaultParams(params *createCmdParams) {
 	if params.Source == "" {
 		params.Source = "develop"
@@ -103,3 +166,54 @@ func fillInDefaultParams(params *createCmdParams) {
 func validateParams(params *createCmdParams) error {
 	return paramutils.ValidateFlagRepositoryParams(&params.Repository)
 }
+
+func readPullRequestTemplate(root string) string {
+	for _, p := range pullRequestTemplatePaths {
+		b, err := os.ReadFile(filepath.Join(root, p))
+		if err == nil {
+			return strings.TrimSpace(string(b))
+		}
+	}
+
+	return ""
+}
+
+// buildEditorTemplate returns the initial content of the editor, the title
+// on the first line followed by the description
+func buildEditorTemplate(
+	params *createCmdParams,
+	commits []string,
+	prTemplate string,
+) string {
+	sections := []string{params.Title}
+	if params.Description != "" {
+		sections = append(sections, params.Description)
+	}
+
+	if prTemplate != "" {
+		sections = append(sections, prTemplate)
+	}
+
+	if len(commits) > 0 {
+		lines := make([]string, 0, len(commits))
+		for _, c := range commits {
+			lines = append(lines, fmt.Sprintf("- %s", strings.SplitN(c, "\n", 2)[0]))
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(sections, "\n\n") + "\n"
+}
+
+// parseEditorContent splits the edited content into the title, the first
+// non-empty line, and the description, the rest of the content
+func parseEditorContent(content string) (string, string) {
+	content = strings.TrimSpace(content)
+	parts := strings.SplitN(content, "\n", 2)
+	title := strings.TrimSpace(parts[0])
+	if len(parts) == 1 {
+		return title, ""
+	}
+
+	return title, strings.TrimSpace(parts[1])
+}
@@ -0,0 +1,168 @@
+package inbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"preq/internal/cli/paramutils"
+	"preq/internal/cli/utils"
+	"preq/internal/persistance"
+	"preq/internal/pkg/client"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inbox",
+		Short: "List pull requests waiting for your review",
+		Long:  `Lists the open pull requests of all visited repositories where you are a reviewer and have not approved yet, the oldest first`,
+		Args:  cobra.NoArgs,
+		Run:   utils.RunCommandWrapper(runCmd),
+	}
+
+	cmd.Flags().StringP(
+		"output",
+		"o",
+		outputFormat_TABLE,
+		fmt.Sprintf("Output format, values - (%s)", strings.Join(outputFormats, ", ")),
+	)
+	cmd.Flags().String("timeout", defaultTimeout.String(), "Time to wait for every repository")
+
+	return cmd
+}
+
+var getVisitedRepos = func() ([]*persistance.PersistanceRepoInfo, error) {
+	return persistance.GetDefault().GetVisited()
+}
+
+var newRepoClient utils.VisitedRepoClientFactory = utils.NewVisitedRepoClient
+
+func runCmd(cmd *cobra.Command, args []string) error {
+	params := &inboxCmdParams{}
+	err := fillFlagInboxCmdParams(paramutils.NewFlagRepo(cmd.Flags()), params)
+	if err != nil {
+		return err
+	}
+
+	return execute(params, os.Stdout, os.Stderr, time.Now())
+}
+
+// inboxItem is a pull request waiting for the review with its repository
+type inboxItem struct {
+	Repository  string
+	PullRequest *client.PullRequest
+}
+
+type outputPullRequest struct {
+	Repository string    `json:"repository"`
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Author     string    `json:"author"`
+	URL        string    `json:"url"`
+	IsDraft    bool      `json:"draft"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+}
+
+func getReviewRequests(c client.Client, repo *client.Repository) ([]*client.PullRequest, error) {
+	return c.GetReviewRequests(&client.GetReviewRequestsOptions{Repository: repo})
+}
+
+// sortByAge sorts the items from the oldest to the newest pull request
+func sortByAge(items []*inboxItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].PullRequest.Created.Before(items[j].PullRequest.Created)
+	})
+}
+
+func execute(params *inboxCmdParams, w io.Writer, errW io.Writer, now time.Time) error {
+	repos, err := getVisitedRepos()
+	if err != nil {
+		return err
+	}
+
+	results := utils.ForEachVisitedRepo(
+		context.Background(),
+		repos,
+		params.Timeout,
+		newRepoClient,
+		getReviewRequests,
+	)
+
+	items := []*inboxItem{}
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(errW, "%s: %s\n", r.Name(), r.Err)
+			continue
+		}
+
+		for _, pr := range r.PullRequests {
+			items = append(items, &inboxItem{Repository: r.Repository.Name, PullRequest: pr})
+		}
+	}
+	sortByAge(items)
+
+	err = writeOutput(w, items, params, now)
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to check %d of %d repositories", failed, len(repos))
+	}
+
+	return nil
+}
+
+func writeOutput(w io.Writer, items []*inboxItem, params *inboxCmdParams, now time.Time) error {
+	if params.Output == outputFormat_JSON {
+		values := make([]*outputPullRequest, 0, len(items))
+		for _, item := range items {
+			values = append(values, &outputPullRequest{
+				Repository: item.Repository,
+				ID:         item.PullRequest.ID,
+				Title:      item.PullRequest.Title,
+				Author:     item.PullRequest.User,
+				URL:        item.PullRequest.URL,
+				IsDraft:    item.PullRequest.IsDraft,
+				Created:    item.PullRequest.Created,
+				Updated:    item.PullRequest.Updated,
+			})
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(values)
+	}
+
+	if len(items) == 0 {
+		_, err := fmt.Fprintln(w, "No pull requests waiting for your review")
+		return err
+	}
+
+	table := uitable.New()
+	table.AddRow("AGE", "REPOSITORY", "#", "TITLE", "AUTHOR", "URL")
+	table.AddRow("---", "----------", "-", "-----", "------", "---")
+	for _, item := range items {
+		table.AddRow(
+			utils.FormatAge(now.Sub(item.PullRequest.Created)),
+			item.Repository,
+			item.PullRequest.ID,
+			item.PullRequest.Title,
+			item.PullRequest.User,
+			item.PullRequest.URL,
+		)
+	}
+	_, err := fmt.Fprintln(w, table.String())
+
+	return err
+}
@@ -0,0 +1,80 @@
+package inbox
+
+import (
+	"bytes"
+	"errors"
+	"preq/internal/cli/paramutils"
+	"preq/internal/cli/utils"
+	"preq/internal/persistance"
+	"preq/internal/pkg/client"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_execute(t *testing.T) {
+	now := time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+	repos := []*persistance.PersistanceRepoInfo{
+		{Name: "owner/first", Provider: "bitbucket"},
+		{Name: "owner/broken", Provider: "github"},
+		{Name: "owner/second", Provider: "github"},
+	}
+
+	defer func(f func() ([]*persistance.PersistanceRepoInfo, error)) { getVisitedRepos = f }(getVisitedRepos)
+	getVisitedRepos = func() ([]*persistance.PersistanceRepoInfo, error) {
+		return repos, nil
+	}
+
+	defer func(f utils.VisitedRepoClientFactory) { newRepoClient = f }(newRepoClient)
+	newRepoClient = func(info *persistance.PersistanceRepoInfo) (client.Client, error) {
+		switch info.Name {
+		case "owner/first":
+			return &client.MockClient{ReviewRequestsValue: []*client.PullRequest{
+				{ID: "1", Title: "Newest", Created: now.Add(-time.Hour)},
+				{ID: "2", Title: "Oldest", Created: now.Add(-10 * 24 * time.Hour)},
+			}}, nil
+		case "owner/second":
+			return &client.MockClient{ReviewRequestsValue: []*client.PullRequest{
+				{ID: "7", Title: "Middle", Created: now.Add(-2 * 24 * time.Hour)},
+			}}, nil
+		}
+
+		return &client.MockClient{ErrorValue: errors.New("bad credentials")}, nil
+	}
+
+	t.Run("lists the review requests sorted by age", func(t *testing.T) {
+		w, errW := &bytes.Buffer{}, &bytes.Buffer{}
+		err := execute(&inboxCmdParams{Output: outputFormat_TABLE, Timeout: time.Second}, w, errW, now)
+
+		assert.EqualError(t, err, "failed to check 1 of 3 repositories")
+		assert.Equal(t, "owner/broken (github): bad credentials\n", errW.String())
+
+		out := w.String()
+		assert.Regexp(t, `(?s)1w\s+owner/first\s+2\s+Oldest.*2d\s+owner/second\s+7\s+Middle.*1h\s+owner/first\s+1\s+Newest`, out)
+	})
+
+	t.Run("writes JSON", func(t *testing.T) {
+		w := &bytes.Buffer{}
+		_ = execute(&inboxCmdParams{Output: outputFormat_JSON, Timeout: time.Second}, w, &bytes.Buffer{}, now)
+		assert.Contains(t, w.String(), `"repository": "owner/second"`)
+	})
+}
+
+func Test_fillFlagInboxCmdParams(t *testing.T) {
+	params := &inboxCmdParams{}
+	err := fillFlagInboxCmdParams(&paramutils.MockPreqFlagSet{StringMap: map[string]interface{}{}}, params)
+	assert.NoError(t, err)
+	assert.Equal(t, outputFormat_TABLE, params.Output)
+	assert.Equal(t, defaultTimeout, params.Timeout)
+
+	err = fillFlagInboxCmdParams(&paramutils.MockPreqFlagSet{StringMap: map[string]interface{}{
+		"output": "yaml",
+	}}, &inboxCmdParams{})
+	assert.Error(t, err)
+
+	err = fillFlagInboxCmdParams(&paramutils.MockPreqFlagSet{StringMap: map[string]interface{}{
+		"timeout": "soon",
+	}}, &inboxCmdParams{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package inbox
+
+import (
+	"fmt"
+	"preq/internal/cli/paramutils"
+	"strings"
+	"time"
+)
+
+const (
+	outputFormat_TABLE = "table"
+	outputFormat_JSON  = "json"
+)
+
+var outputFormats = []string{outputFormat_TABLE, outputFormat_JSON}
+
+const defaultTimeout = 30 * time.Second
+
+type inboxCmdParams struct {
+	Output  string
+	Timeout time.Duration
+}
+
+func fillFlagInboxCmdParams(flags paramutils.FlagRepo, params *inboxCmdParams) error {
+	params.Output = strings.ToLower(flags.GetStringOrDefault("output", outputFormat_TABLE))
+	isKnown := false
+	for _, v := range outputFormats {
+		isKnown = isKnown || v == params.Output
+	}
+	if !isKnown {
+		return fmt.Errorf(
+			"unknown output format '%s', expected one of %s",
+			params.Output,
+			strings.Join(outputFormats, ", "),
+		)
+	}
+
+	timeout := flags.GetStringOrDefault("timeout", defaultTimeout.String())
+	d, err := time.ParseDuration(timeout)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid timeout '%s', expected a positive duration, e.g. 30s", timeout)
+	}
+	params.Timeout = d
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"preq/internal/cli/utils"
+	"preq/internal/persistance"
+	"preq/internal/pkg/client"
+	"time"
+)
+
+const defaultGlobalTimeout = 30 * time.Second
+
+var getVisitedRepos = func() ([]*persistance.PersistanceRepoInfo, error) {
+	return persistance.GetDefault().GetVisited()
+}
+
+var newRepoClient utils.VisitedRepoClientFactory = utils.NewVisitedRepoClient
+
+// listRepository fetches the pull requests of a visited repository, the
+// params are copied since the current user can differ between providers
+func listRepository(params *listCmdParams) utils.VisitedRepoFunc {
+	return func(c client.Client, repo *client.Repository) ([]*client.PullRequest, error) {
+		repoParams := *params
+		err := fillCurrentUserFilter(c, &repoParams)
+		if err != nil {
+			return nil, err
+		}
+
+		return fetchPullRequests(c, repo, &repoParams)
+	}
+}
+
+// executeGlobal lists the pull requests of all visited repositories, the
+// failed repositories are reported to errW without discarding the others
+func executeGlobal(params *listCmdParams, w io.Writer, errW io.Writer) error {
+	repos, err := getVisitedRepos()
+	if err != nil {
+		return err
+	}
+
+	results := utils.ForEachVisitedRepo(
+		context.Background(),
+		repos,
+		params.Timeout,
+		newRepoClient,
+		listRepository(params),
+	)
+
+	items := []*listItem{}
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(errW, "%s: %s\n", r.Name(), r.Err)
+			continue
+		}
+
+		items = append(items, newListItems(r.Repository.Name, r.PullRequests)...)
+	}
+
+	err = writeOutput(w, items, params)
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to list %d of %d repositories", failed, len(repos))
+	}
+
+	return nil
+}
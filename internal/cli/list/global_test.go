@@ -0,0 +1,96 @@
+package list
+
+import (
+	"bytes"
+	"errors"
+	"preq/internal/cli/paramutils"
+	"preq/internal/cli/utils"
+	"preq/internal/persistance"
+	"preq/internal/pkg/client"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type slowClient struct {
+	client.MockClient
+	delay time.Duration
+}
+
+func (c *slowClient) GetPullRequests(o *client.GetPullRequestsOptions) (*client.PullRequestList, error) {
+	time.Sleep(c.delay)
+	return c.MockClient.GetPullRequests(o)
+}
+
+func Test_executeGlobal(t *testing.T) {
+	repos := []*persistance.PersistanceRepoInfo{
+		{Name: "owner/first", Provider: "bitbucket"},
+		{Name: "owner/broken", Provider: "github"},
+		{Name: "owner/slow", Provider: "gitlab"},
+		{Name: "owner/second", Provider: "bitbucket"},
+	}
+
+	defer func(f func() ([]*persistance.PersistanceRepoInfo, error)) { getVisitedRepos = f }(getVisitedRepos)
+	getVisitedRepos = func() ([]*persistance.PersistanceRepoInfo, error) {
+		return repos, nil
+	}
+
+	defer func(f utils.VisitedRepoClientFactory) { newRepoClient = f }(newRepoClient)
+	newRepoClient = func(info *persistance.PersistanceRepoInfo) (client.Client, error) {
+		switch info.Name {
+		case "owner/broken":
+			return nil, errors.New("missing username")
+		case "owner/slow":
+			return &slowClient{MockClient: *mockPages(), delay: time.Second}, nil
+		}
+
+		return mockPages(), nil
+	}
+
+	t.Run("merges the repositories and reports the failures", func(t *testing.T) {
+		w, errW := &bytes.Buffer{}, &bytes.Buffer{}
+		err := executeGlobal(
+			&listCmdParams{Global: true, Timeout: 50 * time.Millisecond, Format: "{{.Repository}}#{{.ID}}"},
+			w,
+			errW,
+		)
+
+		assert.EqualError(t, err, "failed to list 2 of 4 repositories")
+		assert.Equal(t, "owner/first#1\nowner/first#2\nowner/second#1\nowner/second#2\n", w.String())
+		assert.Equal(t, "owner/broken (github): missing username\nowner/slow (gitlab): timed out\n", errW.String())
+	})
+
+	t.Run("fails without repositories", func(t *testing.T) {
+		vErr := errors.New("state err")
+		getVisitedRepos = func() ([]*persistance.PersistanceRepoInfo, error) {
+			return nil, vErr
+		}
+
+		err := executeGlobal(&listCmdParams{Global: true, Timeout: time.Second}, &bytes.Buffer{}, &bytes.Buffer{})
+		assert.ErrorIs(t, err, vErr)
+	})
+}
+
+func Test_fillFlagGlobalParams(t *testing.T) {
+	params := &listCmdParams{}
+	err := fillFlagGlobalParams(&paramutils.MockPreqFlagSet{StringMap: map[string]interface{}{}}, params)
+	assert.NoError(t, err)
+	assert.False(t, params.Global)
+	assert.Equal(t, defaultGlobalTimeout, params.Timeout)
+
+	params = &listCmdParams{}
+	err = fillFlagGlobalParams(&paramutils.MockPreqFlagSet{StringMap: map[string]interface{}{
+		"global":  true,
+		"timeout": "5s",
+	}}, params)
+	assert.NoError(t, err)
+	assert.True(t, params.Global)
+	assert.Equal(t, 5*time.Second, params.Timeout)
+	assert.False(t, params.IsInteractive())
+
+	err = fillFlagGlobalParams(&paramutils.MockPreqFlagSet{StringMap: map[string]interface{}{
+		"timeout": "-1s",
+	}}, &listCmdParams{})
+	assert.Error(t, err)
+}
@@ -9,9 +9,11 @@ import (
 	"preq/internal/cli/utils"
 	"preq/internal/pkg/client"
 	"preq/internal/systemcodes"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gosuri/uilive"
-	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
 )
 
@@ -20,30 +22,219 @@ func New() *cobra.Command {
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List pull requests",
-		Long:    `Lists all pull requests on the web service hosting your origin repository`,
+		Long:    `Lists all pull requests on the web service hosting your origin repository, or of all visited repositories with --global`,
 		Run:     utils.RunCommandWrapper(runCmd),
 	}
 
+	cmd.Flags().StringP(
+		"output",
+		"o",
+		outputFormat_TABLE,
+		fmt.Sprintf("Output format, values - (%s)", strings.Join(outputFormats, ", ")),
+	)
+	cmd.Flags().String("format", "", "Go template applied to every pull request, e.g. '{{.ID}} {{.Title}}'")
+	cmd.Flags().Bool("all", false, "Fetch all pages without prompting")
+	cmd.Flags().Int("limit", 0, "Maximum number of pull requests to fetch")
+	cmd.Flags().String("state", "open", "Pull request state, values - (open, merged, declined, superseded, all)")
+	cmd.Flags().String("author", "", "Only list pull requests of the author")
+	cmd.Flags().String("reviewer", "", "Only list pull requests with the reviewer")
+	cmd.Flags().Bool("mine", false, "Only list your pull requests")
+	cmd.Flags().Bool("needs-my-review", false, "Only list open pull requests you are a reviewer of")
+	cmd.Flags().String("source", "", "Only list pull requests from the source branch")
+	cmd.Flags().String("destination", "", "Only list pull requests into the destination branch")
+	cmd.Flags().String("updated-since", "", "Only list pull requests updated since the date (2006-01-02) or duration (36h, 7d, 2w)")
+	cmd.Flags().String("search", "", "Only list pull requests with the text in the title or description")
+	cmd.Flags().String("timeout", defaultGlobalTimeout.String(), "Time to wait for every repository with --global")
+
 	return cmd
 }
 
 func runCmd(cmd *cobra.Command, args []string) error {
+	params := &listCmdParams{}
+	flags := paramutils.NewFlagRepo(cmd.Flags())
+	err := fillFlagOutputParams(flags, params)
+	if err != nil {
+		return err
+	}
+
+	err = fillFlagFilterParams(flags, params, time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = fillFlagGlobalParams(flags, params)
+	if err != nil {
+		return err
+	}
+
+	if params.Global {
+		return executeGlobal(params, os.Stdout, os.Stderr)
+	}
+
 	cl, repoParams, err := paramutils.GetClientAndRepoParams(cmd.Flags())
 	if err != nil {
 		return err
 	}
 
+	err = fillCurrentUserFilter(cl, params)
+	if err != nil {
+		return err
+	}
+
 	utils.SafelyWriteVisitToState(cmd.Flags(), repoParams)
 
-	return execute(cl, &client.Repository{
+	repo := &client.Repository{
 		Provider: repoParams.Provider,
 		Name:     repoParams.Name,
-	})
+	}
+
+	if params.IsInteractive() {
+		return executeInteractive(cl, repo, params)
+	}
+
+	return execute(cl, repo, params, os.Stdout)
+}
+
+// fillCurrentUserFilter filters by the authenticated user for --mine
+// and --needs-my-review
+func fillCurrentUserFilter(c client.Client, params *listCmdParams) error {
+	if !params.Mine && !params.NeedsMyReview {
+		return nil
+	}
+
+	u, err := c.GetCurrentUser()
+	if err != nil {
+		return fmt.Errorf("could not determine the current user: %w", err)
+	}
+
+	if params.Mine {
+		params.Filter.Author = u.ID
+	}
+
+	if params.NeedsMyReview {
+		params.Filter.Reviewer = u.ID
+		params.State = client.PullRequestState_OPEN
+	}
+
+	return nil
+}
+
+// fetchPullRequests loads the pull requests without prompting, every page
+// is loaded with all set, otherwise only the first one. The limit loads as
+// many pages as needed. The reviews and comment counts are loaded for the
+// outputs which include them.
+func fetchPullRequests(
+	c client.Client,
+	repo *client.Repository,
+	params *listCmdParams,
+) ([]*client.PullRequest, error) {
+	prs, err := fetchPullRequestPages(c, repo, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Output != outputFormat_TABLE || params.Format != "" {
+		err = fillMiscInfo(c, repo, prs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return prs, nil
+}
+
+// miscInfoConcurrency limits the number of pull requests whose reviews are
+// requested at once
+const miscInfoConcurrency = 8
+
+// fillMiscInfo loads the reviews and the comment counts of the pull
+// requests, the list endpoints of the providers do not return them
+func fillMiscInfo(c client.Client, repo *client.Repository, prs []*client.PullRequest) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, miscInfoConcurrency)
+	)
+	for _, pr := range prs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(pr *client.PullRequest) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := c.FillMiscInfoAsync(repo, pr)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to load the reviews of #%s: %w", pr.ID, err)
+				}
+				mu.Unlock()
+			}
+		}(pr)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+func fetchPullRequestPages(
+	c client.Client,
+	repo *client.Repository,
+	params *listCmdParams,
+) ([]*client.PullRequest, error) {
+	values := []*client.PullRequest{}
+	nextURL := ""
+
+	for {
+		prs, err := c.GetPullRequests(&client.GetPullRequestsOptions{
+			Repository: repo,
+			State:      params.State,
+			Next:       nextURL,
+			Filter:     &params.Filter,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if prs == nil {
+			break
+		}
+
+		values = append(values, prs.Values...)
+		if params.Limit > 0 && len(values) >= params.Limit {
+			return values[:params.Limit], nil
+		}
+
+		// Pages can be empty when the provider filters them locally
+		nextURL = prs.NextURL
+		if nextURL == "" || (!params.All && params.Limit == 0 && len(values) > 0) {
+			break
+		}
+	}
+
+	return values, nil
 }
 
 func execute(
 	c client.Client,
 	repo *client.Repository,
+	params *listCmdParams,
+	w io.Writer,
+) error {
+	prs, err := fetchPullRequests(c, repo, params)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(w, newListItems(repo.Name, prs), params)
+}
+
+func executeInteractive(
+	c client.Client,
+	repo *client.Repository,
+	params *listCmdParams,
 ) error {
 	nextURL := ""
 	reader := bufio.NewReader(os.Stdin)
@@ -52,15 +243,14 @@ func execute(
 	defer writer.Stop()
 	writer.Start()
 
-	table := uitable.New()
-	table.AddRow("#", "TITLE", "SRC/DEST", "URL")
-	table.AddRow("-", "-----", "--------", "---")
+	table := newTable(false)
 
 	for {
 		prs, err := c.GetPullRequests(&client.GetPullRequestsOptions{
 			Repository: repo,
-			State:      client.PullRequestState_OPEN,
+			State:      params.State,
 			Next:       nextURL,
+			Filter:     &params.Filter,
 		})
 		if err != nil {
 			fmt.Println(err)
@@ -69,14 +259,7 @@ func execute(
 
 		nextURL = prs.NextURL
 
-		for _, v := range prs.Values {
-			table.AddRow(
-				v.ID,
-				v.Title,
-				fmt.Sprintf("%s -> %s", v.Source, v.Destination),
-				v.URL,
-			)
-		}
+		addTableRows(table, newListItems(repo.Name, prs.Values), false)
 
 		fmt.Fprintln(writer, table.String())
 
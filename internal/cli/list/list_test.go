@@ -0,0 +1,253 @@
+package list
+
+import (
+	"bytes"
+	"errors"
+	"preq/internal/cli/paramutils"
+	"preq/internal/pkg/client"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockPages() *client.MockClient {
+	return &client.MockClient{PullRequestPages: map[string]*client.PullRequestList{
+		"": {
+			NextURL: "page2",
+			Values:  []*client.PullRequest{{ID: "1", Title: "First"}, {ID: "2", Title: "Second"}},
+		},
+		"page2": {
+			Values: []*client.PullRequest{{ID: "3", Title: "Third"}},
+		},
+	}}
+}
+
+func ids(prs []*client.PullRequest) []string {
+	values := []string{}
+	for _, pr := range prs {
+		values = append(values, pr.ID)
+	}
+
+	return values
+}
+
+func Test_fetchPullRequests(t *testing.T) {
+	repo := &client.Repository{Name: "owner/repo"}
+
+	t.Run("fetches the first page by default", func(t *testing.T) {
+		prs, err := fetchPullRequests(mockPages(), repo, &listCmdParams{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "2"}, ids(prs))
+	})
+
+	t.Run("fetches every page with all", func(t *testing.T) {
+		prs, err := fetchPullRequests(mockPages(), repo, &listCmdParams{All: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "2", "3"}, ids(prs))
+	})
+
+	t.Run("fetches pages up to the limit", func(t *testing.T) {
+		prs, err := fetchPullRequests(mockPages(), repo, &listCmdParams{Limit: 3})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "2", "3"}, ids(prs))
+
+		prs, err = fetchPullRequests(mockPages(), repo, &listCmdParams{All: true, Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1"}, ids(prs))
+	})
+
+	t.Run("returns the client error", func(t *testing.T) {
+		vErr := errors.New("list err")
+		_, err := fetchPullRequests(&client.MockClient{ErrorValue: vErr}, repo, &listCmdParams{})
+		assert.ErrorIs(t, err, vErr)
+	})
+}
+
+// miscInfoClient sets the reviews like the providers do in
+// FillMiscInfoAsync
+type miscInfoClient struct {
+	*client.MockClient
+}
+
+func (c *miscInfoClient) FillMiscInfoAsync(repo *client.Repository, pr *client.PullRequest) error {
+	pr.CommentCount = 2
+	pr.Approvals = []*client.PullRequestApproval{{User: "bob-" + pr.ID}}
+	pr.ChangesRequests = []*client.PullRequestChangesRequest{{User: "carol"}}
+	return nil
+}
+
+func Test_execute(t *testing.T) {
+	repo := &client.Repository{Name: "owner/repo"}
+
+	t.Run("fills the reviews for the machine-readable outputs", func(t *testing.T) {
+		w := &bytes.Buffer{}
+		err := execute(&miscInfoClient{mockPages()}, repo, &listCmdParams{Output: outputFormat_JSON, All: true}, w)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), `"commentCount": 2`)
+		assert.Contains(t, w.String(), `"user": "bob-3"`)
+		assert.Contains(t, w.String(), `"changesRequests": [
+      {
+        "user": "carol"`)
+	})
+
+	t.Run("does not request the reviews for the table", func(t *testing.T) {
+		prs, err := fetchPullRequests(&miscInfoClient{mockPages()}, repo, &listCmdParams{Output: outputFormat_TABLE})
+		assert.NoError(t, err)
+		assert.Empty(t, prs[0].Approvals)
+	})
+}
+
+func Test_writeOutput(t *testing.T) {
+	created := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	items := newListItems("owner/repo", []*client.PullRequest{{
+		ID:          "1",
+		Title:       "Title, with comma",
+		User:        "alice",
+		State:       client.PullRequestState_OPEN,
+		Source:      client.PullRequestBranch{Name: "feature", Hash: "abc"},
+		Destination: client.PullRequestBranch{Name: "main", Hash: "def"},
+		Created:     created,
+		Updated:     created,
+		Approvals:   []*client.PullRequestApproval{{User: "bob"}, {User: "carol"}},
+	}})
+
+	t.Run("executes the template", func(t *testing.T) {
+		w := &bytes.Buffer{}
+		err := writeOutput(w, items, &listCmdParams{Format: "{{.ID}} {{.Source.Name}} {{len .Approvals}}"})
+		assert.NoError(t, err)
+		assert.Equal(t, "1 feature 2\n", w.String())
+	})
+
+	t.Run("fails for an invalid template", func(t *testing.T) {
+		err := writeOutput(&bytes.Buffer{}, items, &listCmdParams{Format: "{{.ID"})
+		assert.Error(t, err)
+	})
+
+	t.Run("writes JSON", func(t *testing.T) {
+		w := &bytes.Buffer{}
+		err := writeOutput(w, items, &listCmdParams{Output: outputFormat_JSON})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), `"destination": {`)
+		assert.Contains(t, w.String(), `"user": "carol"`)
+	})
+
+	t.Run("writes CSV", func(t *testing.T) {
+		w := &bytes.Buffer{}
+		err := writeOutput(w, items, &listCmdParams{Output: outputFormat_CSV})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "1,\"Title, with comma\",,alice,,OPEN,false,feature,abc,main,def,2023-03-01T12:00:00Z,2023-03-01T12:00:00Z,0,bob;carol,\n")
+	})
+
+	t.Run("adds the repository when global", func(t *testing.T) {
+		w := &bytes.Buffer{}
+		err := writeOutput(w, items, &listCmdParams{Output: outputFormat_CSV, Global: true})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "repository,id,")
+		assert.Contains(t, w.String(), "owner/repo,1,")
+
+		w = &bytes.Buffer{}
+		err = writeOutput(w, items, &listCmdParams{Output: outputFormat_TABLE, Global: true})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "REPOSITORY")
+
+		w = &bytes.Buffer{}
+		err = writeOutput(w, items, &listCmdParams{Format: "{{.Repository}}#{{.ID}}"})
+		assert.NoError(t, err)
+		assert.Equal(t, "owner/repo#1\n", w.String())
+	})
+}
+
+func Test_fillFlagOutputParams(t *testing.T) {
+	params := &listCmdParams{}
+	err := fillFlagOutputParams(&paramutils.MockPreqFlagSet{StringMap: map[string]interface{}{
+		"output": "JSON",
+		"limit":  5,
+	}}, params)
+	assert.NoError(t, err)
+	assert.Equal(t, &listCmdParams{Output: outputFormat_JSON, Limit: 5}, params)
+	assert.False(t, params.IsInteractive())
+
+	err = fillFlagOutputParams(&paramutils.MockPreqFlagSet{StringMap: map[string]interface{}{
+		"output": "xml",
+	}}, &listCmdParams{})
+	assert.Error(t, err)
+
+	err = fillFlagOutputParams(&paramutils.MockPreqFlagSet{StringMap: map[string]interface{}{
+		"output": "json",
+		"format": "{{.ID}}",
+	}}, &listCmdParams{})
+	assert.Error(t, err)
+
+	params = &listCmdParams{}
+	fillFlagOutputParams(&paramutils.MockPreqFlagSet{}, params)
+	assert.True(t, params.IsInteractive())
+}
+
+func Test_parseUpdatedSince(t *testing.T) {
+	now := time.Date(2023, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	for input, expected := range map[string]time.Time{
+		"2023-03-01":           time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+		"2023-03-01T08:00:00Z": time.Date(2023, 3, 1, 8, 0, 0, 0, time.UTC),
+		"36h":                  time.Date(2023, 3, 9, 0, 0, 0, 0, time.UTC),
+		"7d":                   time.Date(2023, 3, 3, 12, 0, 0, 0, time.UTC),
+		"1w":                   time.Date(2023, 3, 3, 12, 0, 0, 0, time.UTC),
+	} {
+		v, err := parseUpdatedSince(input, now)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, v, input)
+	}
+
+	_, err := parseUpdatedSince("last week", now)
+	assert.Error(t, err)
+}
+
+func Test_fillFlagFilterParams(t *testing.T) {
+	now := time.Now()
+
+	t.Run("defaults to open pull requests", func(t *testing.T) {
+		params := &listCmdParams{}
+		err := fillFlagFilterParams(&paramutils.MockPreqFlagSet{}, params, now)
+		assert.NoError(t, err)
+		assert.Equal(t, client.PullRequestState(client.PullRequestState_OPEN), params.State)
+	})
+
+	t.Run("lists all states", func(t *testing.T) {
+		params := &listCmdParams{}
+		err := fillFlagFilterParams(&paramutils.MockPreqFlagSet{StringMap: map[string]interface{}{
+			"state":  "all",
+			"author": "alice",
+		}}, params, now)
+		assert.NoError(t, err)
+		assert.Equal(t, client.PullRequestState(""), params.State)
+		assert.Equal(t, "alice", params.Filter.Author)
+	})
+
+	for name, flags := range map[string]map[string]interface{}{
+		"unknown state":              {"state": "closed"},
+		"mine with author":           {"mine": true, "author": "alice"},
+		"needs review with reviewer": {"needs-my-review": true, "reviewer": "bob"},
+		"needs review when merged":   {"needs-my-review": true, "state": "merged"},
+		"invalid update time":        {"updated-since": "yesterday"},
+	} {
+		t.Run("fails for "+name, func(t *testing.T) {
+			err := fillFlagFilterParams(&paramutils.MockPreqFlagSet{StringMap: flags}, &listCmdParams{}, now)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_fillCurrentUserFilter(t *testing.T) {
+	c := &client.MockClient{CurrentUserValue: &client.User{ID: "{me}"}}
+
+	params := &listCmdParams{Mine: true, NeedsMyReview: true}
+	assert.NoError(t, fillCurrentUserFilter(c, params))
+	assert.Equal(t, "{me}", params.Filter.Author)
+	assert.Equal(t, "{me}", params.Filter.Reviewer)
+	assert.Equal(t, client.PullRequestState(client.PullRequestState_OPEN), params.State)
+
+	vErr := errors.New("user err")
+	err := fillCurrentUserFilter(&client.MockClient{ErrorValue: vErr}, &listCmdParams{Mine: true})
+	assert.ErrorIs(t, err, vErr)
+}
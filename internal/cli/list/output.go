@@ -0,0 +1,227 @@
+package list
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"preq/internal/pkg/client"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"gopkg.in/yaml.v3"
+)
+
+type outputBranch struct {
+	Name string `json:"name" yaml:"name"`
+	Hash string `json:"hash" yaml:"hash"`
+}
+
+type outputReview struct {
+	User    string    `json:"user" yaml:"user"`
+	Created time.Time `json:"created" yaml:"created"`
+}
+
+// outputPullRequest is the machine-readable representation of a pull
+// request, with stable lower case keys
+type outputPullRequest struct {
+	Repository      string         `json:"repository,omitempty" yaml:"repository,omitempty"`
+	ID              string         `json:"id" yaml:"id"`
+	Title           string         `json:"title" yaml:"title"`
+	Description     string         `json:"description" yaml:"description"`
+	Author          string         `json:"author" yaml:"author"`
+	URL             string         `json:"url" yaml:"url"`
+	State           string         `json:"state" yaml:"state"`
+	IsDraft         bool           `json:"draft" yaml:"draft"`
+	Source          outputBranch   `json:"source" yaml:"source"`
+	Destination     outputBranch   `json:"destination" yaml:"destination"`
+	Created         time.Time      `json:"created" yaml:"created"`
+	Updated         time.Time      `json:"updated" yaml:"updated"`
+	CommentCount    int            `json:"commentCount" yaml:"commentCount"`
+	Approvals       []outputReview `json:"approvals" yaml:"approvals"`
+	ChangesRequests []outputReview `json:"changesRequests" yaml:"changesRequests"`
+}
+
+// listItem is a listed pull request, the repository is only set when
+// listing several repositories. It is the data of the format templates.
+type listItem struct {
+	*client.PullRequest
+	Repository string
+}
+
+func newListItems(repo string, prs []*client.PullRequest) []*listItem {
+	items := make([]*listItem, 0, len(prs))
+	for _, pr := range prs {
+		items = append(items, &listItem{PullRequest: pr, Repository: repo})
+	}
+
+	return items
+}
+
+func newOutputPullRequest(item *listItem) *outputPullRequest {
+	pr := item.PullRequest
+	o := &outputPullRequest{
+		Repository:      item.Repository,
+		ID:              pr.ID,
+		Title:           pr.Title,
+		Description:     pr.Description,
+		Author:          pr.User,
+		URL:             pr.URL,
+		State:           string(pr.State),
+		IsDraft:         pr.IsDraft,
+		Source:          outputBranch{Name: pr.Source.Name, Hash: pr.Source.Hash},
+		Destination:     outputBranch{Name: pr.Destination.Name, Hash: pr.Destination.Hash},
+		Created:         pr.Created,
+		Updated:         pr.Updated,
+		CommentCount:    pr.CommentCount,
+		Approvals:       []outputReview{},
+		ChangesRequests: []outputReview{},
+	}
+
+	for _, a := range pr.Approvals {
+		o.Approvals = append(o.Approvals, outputReview{User: a.User, Created: a.Created})
+	}
+	for _, r := range pr.ChangesRequests {
+		o.ChangesRequests = append(o.ChangesRequests, outputReview{User: r.User, Created: r.Created})
+	}
+
+	return o
+}
+
+func newOutputPullRequests(items []*listItem) []*outputPullRequest {
+	values := make([]*outputPullRequest, 0, len(items))
+	for _, item := range items {
+		values = append(values, newOutputPullRequest(item))
+	}
+
+	return values
+}
+
+func writeOutput(w io.Writer, items []*listItem, params *listCmdParams) error {
+	if params.Format != "" {
+		return writeTemplate(w, items, params.Format)
+	}
+
+	switch params.Output {
+	case outputFormat_JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(newOutputPullRequests(items))
+	case outputFormat_YAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		return encoder.Encode(newOutputPullRequests(items))
+	case outputFormat_CSV:
+		return writeDelimited(w, items, ',', params.Global)
+	case outputFormat_TSV:
+		return writeDelimited(w, items, '\t', params.Global)
+	}
+
+	table := newTable(params.Global)
+	addTableRows(table, items, params.Global)
+	_, err := fmt.Fprintln(w, table.String())
+
+	return err
+}
+
+// writeTemplate executes the Go template for every pull request, the
+// template data is the client.PullRequest with the repository name
+func writeTemplate(w io.Writer, items []*listItem, format string) error {
+	t, err := template.New("format").Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+
+	for _, item := range items {
+		err = t.Execute(w, item)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+func reviewUsers(reviews []outputReview) string {
+	users := make([]string, 0, len(reviews))
+	for _, r := range reviews {
+		users = append(users, r.User)
+	}
+
+	return strings.Join(users, ";")
+}
+
+func writeDelimited(w io.Writer, items []*listItem, separator rune, showRepository bool) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = separator
+
+	header := []string{
+		"id", "title", "description", "author", "url", "state", "draft",
+		"source", "source_hash", "destination", "destination_hash",
+		"created", "updated", "comments", "approvals", "changes_requests",
+	}
+	if showRepository {
+		header = append([]string{"repository"}, header...)
+	}
+	writer.Write(header)
+
+	for _, pr := range newOutputPullRequests(items) {
+		row := []string{
+			pr.ID,
+			pr.Title,
+			pr.Description,
+			pr.Author,
+			pr.URL,
+			pr.State,
+			strconv.FormatBool(pr.IsDraft),
+			pr.Source.Name,
+			pr.Source.Hash,
+			pr.Destination.Name,
+			pr.Destination.Hash,
+			pr.Created.Format(time.RFC3339),
+			pr.Updated.Format(time.RFC3339),
+			strconv.Itoa(pr.CommentCount),
+			reviewUsers(pr.Approvals),
+			reviewUsers(pr.ChangesRequests),
+		}
+		if showRepository {
+			row = append([]string{pr.Repository}, row...)
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func newTable(showRepository bool) *uitable.Table {
+	table := uitable.New()
+	if showRepository {
+		table.AddRow("REPOSITORY", "#", "TITLE", "SRC/DEST", "URL")
+		table.AddRow("----------", "-", "-----", "--------", "---")
+	} else {
+		table.AddRow("#", "TITLE", "SRC/DEST", "URL")
+		table.AddRow("-", "-----", "--------", "---")
+	}
+
+	return table
+}
+
+func addTableRows(table *uitable.Table, items []*listItem, showRepository bool) {
+	for _, v := range items {
+		row := []interface{}{
+			v.ID,
+			v.Title,
+			fmt.Sprintf("%s -> %s", v.Source.Name, v.Destination.Name),
+			v.URL,
+		}
+		if showRepository {
+			row = append([]interface{}{v.Repository}, row...)
+		}
+		table.AddRow(row...)
+	}
+}
@@ -1,18 +1,63 @@
 package list
 
 import (
+	"errors"
+	"fmt"
 	"preq/internal/cli/paramutils"
 	"preq/internal/configutils"
 	"preq/internal/errcodes"
 	"preq/internal/gitutils"
 	"preq/internal/pkg/client"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
+const (
+	outputFormat_TABLE = "table"
+	outputFormat_JSON  = "json"
+	outputFormat_YAML  = "yaml"
+	outputFormat_CSV   = "csv"
+	outputFormat_TSV   = "tsv"
+)
+
+var outputFormats = []string{
+	outputFormat_TABLE,
+	outputFormat_JSON,
+	outputFormat_YAML,
+	outputFormat_CSV,
+	outputFormat_TSV,
+}
+
+var states = map[string]client.PullRequestState{
+	"open":       client.PullRequestState_OPEN,
+	"merged":     client.PullRequestState_MERGED,
+	"declined":   client.PullRequestState_DECLINED,
+	"superseded": client.PullRequestState_SUPERSEDED,
+	"all":        "",
+}
+
 type listCmdParams struct {
-	Repository paramutils.RepositoryParams
+	Repository    paramutils.RepositoryParams
+	Output        string
+	Format        string
+	All           bool
+	Limit         int
+	State         client.PullRequestState
+	Filter        client.PullRequestFilter
+	Mine          bool
+	NeedsMyReview bool
+	Global        bool
+	Timeout       time.Duration
+}
+
+// IsInteractive reports whether the pages are loaded on demand, only the
+// default table output of a single repository without a page limit is
+// interactive
+func (p *listCmdParams) IsInteractive() bool {
+	return p.Output == outputFormat_TABLE && p.Format == "" && !p.All && p.Limit == 0 && !p.Global
 }
 
 var getWorkingDirectoryRepo = gitutils.GetWorkingDirectoryRepo
@@ -43,3 +88,111 @@ func fillFlagListCmdParams(cmd *cobra.Command, params *listCmdParams) error {
 
 	return nil
 }
+
+func fillFlagOutputParams(flags paramutils.FlagRepo, params *listCmdParams) error {
+	params.Output = strings.ToLower(flags.GetStringOrDefault("output", outputFormat_TABLE))
+	params.Format = flags.GetStringOrDefault("format", "")
+	params.All = flags.GetBoolOrDefault("all", false)
+	params.Limit = flags.GetIntOrDefault("limit", 0)
+
+	isKnown := false
+	for _, v := range outputFormats {
+		isKnown = isKnown || v == params.Output
+	}
+	if !isKnown {
+		return fmt.Errorf(
+			"unknown output format '%s', expected one of %s",
+			params.Output,
+			strings.Join(outputFormats, ", "),
+		)
+	}
+
+	if params.Format != "" && flags.Changed("output") {
+		return errors.New("--format and --output cannot be used together")
+	}
+
+	if params.Limit < 0 {
+		return errors.New("--limit cannot be negative")
+	}
+
+	return nil
+}
+
+// fillFlagGlobalParams reads the flags for listing all visited repositories
+func fillFlagGlobalParams(flags paramutils.FlagRepo, params *listCmdParams) error {
+	params.Global = flags.GetBoolOrDefault("global", false)
+
+	timeout := flags.GetStringOrDefault("timeout", defaultGlobalTimeout.String())
+	d, err := time.ParseDuration(timeout)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid timeout '%s', expected a positive duration, e.g. 30s", timeout)
+	}
+	params.Timeout = d
+
+	return nil
+}
+
+// parseUpdatedSince parses a date, a timestamp or a duration before now,
+// e.g. 2023-03-01, 2023-03-01T12:00:00Z, 36h, 7d or 2w
+func parseUpdatedSince(s string, now time.Time) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+
+	units := map[string]int{"d": 1, "w": 7}
+	for suffix, days := range units {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); err == nil && strings.HasSuffix(s, suffix) {
+			return now.AddDate(0, 0, -n*days), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time '%s', expected a date (2006-01-02) or a duration (36h, 7d, 2w)", s)
+}
+
+func fillFlagFilterParams(flags paramutils.FlagRepo, params *listCmdParams, now time.Time) error {
+	state := strings.ToLower(flags.GetStringOrDefault("state", "open"))
+	v, ok := states[state]
+	if !ok {
+		return fmt.Errorf("unknown state '%s', expected one of open, merged, declined, superseded, all", state)
+	}
+	params.State = v
+
+	params.Mine = flags.GetBoolOrDefault("mine", false)
+	params.NeedsMyReview = flags.GetBoolOrDefault("needs-my-review", false)
+	params.Filter = client.PullRequestFilter{
+		Author:      flags.GetStringOrDefault("author", ""),
+		Reviewer:    flags.GetStringOrDefault("reviewer", ""),
+		Source:      flags.GetStringOrDefault("source", ""),
+		Destination: flags.GetStringOrDefault("destination", ""),
+		Search:      flags.GetStringOrDefault("search", ""),
+	}
+
+	if params.Mine && params.Filter.Author != "" {
+		return errors.New("--mine and --author cannot be used together")
+	}
+
+	if params.NeedsMyReview {
+		if params.Filter.Reviewer != "" {
+			return errors.New("--needs-my-review and --reviewer cannot be used together")
+		}
+		if flags.Changed("state") && params.State != client.PullRequestState_OPEN {
+			return errors.New("--needs-my-review only lists open pull requests")
+		}
+	}
+
+	if since := flags.GetStringOrDefault("updated-since", ""); since != "" {
+		t, err := parseUpdatedSince(since, now)
+		if err != nil {
+			return err
+		}
+		params.Filter.UpdatedSince = t
+	}
+
+	return nil
+}
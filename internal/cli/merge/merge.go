@@ -0,0 +1,76 @@
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"preq/internal/cli/paramutils"
+	"preq/internal/cli/utils"
+	"preq/internal/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "merge [ID]",
+		Aliases: []string{"mg"},
+		Short:   "Merge pull request",
+		Long:    `Merges a pull request on the web service hosting your origin repository`,
+		Args:    cobra.ExactArgs(1),
+		Run:     utils.RunCommandWrapper(runCmd),
+	}
+
+	cmd.Flags().
+		String("strategy", "", "merge strategy, values - (merge_commit, squash, fast_forward) (default repository setting)")
+	cmd.Flags().
+		StringP("message", "m", "", "commit message of the merge commit")
+
+	return cmd
+}
+
+func runCmd(cmd *cobra.Command, args []string) error {
+	cmdArgs := parseArgs(args)
+
+	params := &mergeCmdParams{}
+	err := fillFlagMergeCmdParams(paramutils.NewFlagRepo(cmd.Flags()), params)
+	if err != nil {
+		return err
+	}
+
+	cl, repoParams, err := paramutils.GetClientAndRepoParams(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	utils.SafelyWriteVisitToState(cmd.Flags(), repoParams)
+
+	return execute(cl, cmdArgs, params, &client.Repository{
+		Provider: repoParams.Provider,
+		Name:     repoParams.Name,
+	})
+}
+
+func execute(
+	c client.Client,
+	args *cmdArgs,
+	params *mergeCmdParams,
+	repo *client.Repository,
+) error {
+	if args.ID == "" {
+		return errors.New("missing pull request ID")
+	}
+
+	_, err := c.Merge(&client.MergeOptions{
+		Repository: repo,
+		ID:         args.ID,
+		Strategy:   params.Strategy,
+		Message:    params.Message,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Merged pull request #%s\n", args.ID)
+
+	return nil
+}
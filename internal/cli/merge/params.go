@@ -0,0 +1,36 @@
+package merge
+
+import (
+	"preq/internal/cli/paramutils"
+	"preq/internal/pkg/client"
+)
+
+type cmdArgs struct {
+	ID string
+}
+
+type mergeCmdParams struct {
+	Strategy client.MergeStrategy
+	Message  string
+}
+
+func parseArgs(args []string) *cmdArgs {
+	return &cmdArgs{ID: paramutils.ParseIDArg(args)}
+}
+
+func fillFlagMergeCmdParams(
+	flags paramutils.FlagRepo,
+	params *mergeCmdParams,
+) error {
+	strategy, err := client.ParseMergeStrategy(
+		flags.GetStringOrDefault("strategy", string(params.Strategy)),
+	)
+	if err != nil {
+		return err
+	}
+
+	params.Strategy = strategy
+	params.Message = flags.GetStringOrDefault("message", params.Message)
+
+	return nil
+}
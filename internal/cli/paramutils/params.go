@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 )
 
 type RepositoryParams struct {
@@ -26,6 +27,9 @@ func (p *RepositoryParams) SetDefault() {
 type FlagRepo interface {
 	GetStringOrDefault(flag, d string) string
 	GetBoolOrDefault(flag string, d bool) bool
+	GetStringSliceOrDefault(flag string, d []string) []string
+	GetIntOrDefault(flag string, d int) int
+	Changed(flag string) bool
 }
 
 func NewFlagRepo(flags *pflag.FlagSet) FlagRepo {
@@ -54,6 +58,31 @@ func (fs *PFlagSetWrapper) GetBoolOrDefault(flag string, d bool) bool {
 	return s
 }
 
+func (fs *PFlagSetWrapper) GetStringSliceOrDefault(
+	flag string,
+	d []string,
+) []string {
+	s, err := fs.Flags.GetStringSlice(flag)
+	if err != nil || len(s) == 0 {
+		return d
+	}
+
+	return s
+}
+
+func (fs *PFlagSetWrapper) GetIntOrDefault(flag string, d int) int {
+	i, err := fs.Flags.GetInt(flag)
+	if err != nil {
+		return d
+	}
+
+	return i
+}
+
+func (fs *PFlagSetWrapper) Changed(flag string) bool {
+	return fs.Flags.Changed(flag)
+}
+
 type localRepositoryParamsFiller struct{}
 
 type viperConfigParamsFiller struct{}
@@ -86,6 +115,20 @@ func GetRepoPath(flagSet *pflag.FlagSet) (string, error) {
 	return path, nil
 }
 
+// GetProviderAliases returns the configured host aliases of every
+// repository provider, e.g. a self-hosted instance's domain
+func GetProviderAliases(config *viper.Viper) map[client.RepositoryProvider][]string {
+	aliases := make(map[client.RepositoryProvider][]string)
+	for _, p := range client.RepositoryProviders() {
+		pa := config.GetStringSlice(fmt.Sprintf("%s.aliases", p))
+		for _, a := range pa {
+			aliases[p] = append(aliases[p], a)
+		}
+	}
+
+	return aliases
+}
+
 func GetRepoUtilsAndParams(
 	flagSet *pflag.FlagSet,
 ) (gitutils.GitUtilsClient, *RepositoryParams, error) {
@@ -102,17 +145,7 @@ func GetRepoUtilsAndParams(
 		return nil, nil, err
 	}
 
-	aliases := make(map[client.RepositoryProvider][]string)
-	providers := []client.RepositoryProvider{
-		client.RepositoryProviderEnum.BITBUCKET,
-		client.RepositoryProviderEnum.GITHUB,
-	}
-	for _, p := range providers {
-		pa := config.GetStringSlice(fmt.Sprintf("%s.aliases", p))
-		for _, a := range pa {
-			aliases[p] = append(aliases[p], a)
-		}
-	}
+	aliases := GetProviderAliases(config)
 
 	info, err := git.GetRemoteInfo(aliases)
 	if err != nil {
@@ -139,17 +172,7 @@ func GetClientAndRepoParams(
 		return nil, nil, err
 	}
 
-	aliases := make(map[client.RepositoryProvider][]string)
-	providers := []client.RepositoryProvider{
-		client.RepositoryProviderEnum.BITBUCKET,
-		client.RepositoryProviderEnum.GITHUB,
-	}
-	for _, p := range providers {
-		pa := config.GetStringSlice(fmt.Sprintf("%s.aliases", p))
-		for _, a := range pa {
-			aliases[p] = append(aliases[p], a)
-		}
-	}
+	aliases := GetProviderAliases(config)
 
 	git, err := gitutils.GetRepo(path)
 	if err != nil {
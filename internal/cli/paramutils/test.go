@@ -19,3 +19,27 @@ func (fs *MockPreqFlagSet) GetBoolOrDefault(flag string, d bool) bool {
 
 	return d
 }
+
+func (fs *MockPreqFlagSet) GetStringSliceOrDefault(
+	flag string,
+	d []string,
+) []string {
+	if val, ok := fs.StringMap[flag]; ok {
+		return val.([]string)
+	}
+
+	return d
+}
+
+func (fs *MockPreqFlagSet) GetIntOrDefault(flag string, d int) int {
+	if val, ok := fs.StringMap[flag]; ok {
+		return val.(int)
+	}
+
+	return d
+}
+
+func (fs *MockPreqFlagSet) Changed(flag string) bool {
+	_, ok := fs.StringMap[flag]
+	return ok
+}
@@ -0,0 +1,11 @@
+package ready
+
+import "preq/internal/cli/paramutils"
+
+type cmdArgs struct {
+	ID string
+}
+
+func parseArgs(args []string) *cmdArgs {
+	return &cmdArgs{ID: paramutils.ParseIDArg(args)}
+}
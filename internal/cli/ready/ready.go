@@ -0,0 +1,63 @@
+package ready
+
+import (
+	"errors"
+	"fmt"
+	"preq/internal/cli/paramutils"
+	"preq/internal/cli/utils"
+	"preq/internal/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ready [ID]",
+		Short: "Mark pull request as ready for review",
+		Long:  `Marks a draft pull request as ready for review on the web service hosting your origin repository`,
+		Args:  cobra.ExactArgs(1),
+		Run:   utils.RunCommandWrapper(runCmd),
+	}
+
+	return cmd
+}
+
+func runCmd(cmd *cobra.Command, args []string) error {
+	cmdArgs := parseArgs(args)
+
+	cl, repoParams, err := paramutils.GetClientAndRepoParams(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	utils.SafelyWriteVisitToState(cmd.Flags(), repoParams)
+
+	return execute(cl, cmdArgs, &client.Repository{
+		Provider: repoParams.Provider,
+		Name:     repoParams.Name,
+	})
+}
+
+func execute(
+	c client.Client,
+	args *cmdArgs,
+	repo *client.Repository,
+) error {
+	if args.ID == "" {
+		return errors.New("missing pull request ID")
+	}
+
+	draft := false
+	_, err := c.UpdatePullRequest(&client.UpdatePullRequestOptions{
+		Repository: repo,
+		ID:         args.ID,
+		Draft:      &draft,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Marked pull request #%s as ready for review\n", args.ID)
+
+	return nil
+}
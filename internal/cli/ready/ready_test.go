@@ -0,0 +1,29 @@
+package ready
+
+import (
+	"errors"
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_execute(t *testing.T) {
+	repo := &client.Repository{Name: "owner/repo"}
+
+	t.Run("fails without an ID", func(t *testing.T) {
+		err := execute(&client.MockClient{}, &cmdArgs{}, repo)
+		assert.Error(t, err)
+	})
+
+	t.Run("returns the client error", func(t *testing.T) {
+		vErr := errors.New("update err")
+		err := execute(&client.MockClient{ErrorValue: vErr}, &cmdArgs{ID: "1"}, repo)
+		assert.ErrorIs(t, err, vErr)
+	})
+
+	t.Run("succeeds otherwise", func(t *testing.T) {
+		err := execute(&client.MockClient{}, &cmdArgs{ID: "1"}, repo)
+		assert.NoError(t, err)
+	})
+}
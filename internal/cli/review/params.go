@@ -0,0 +1,21 @@
+package review
+
+import (
+	"preq/internal/cli/paramutils"
+)
+
+type reviewCmdParams struct {
+	Worktree bool
+}
+
+type cmdArgs struct {
+	ID string
+}
+
+func parseArgs(args []string) *cmdArgs {
+	return &cmdArgs{ID: paramutils.ParseIDArg(args)}
+}
+
+func fillFlagReviewCmdParams(flags paramutils.FlagRepo, params *reviewCmdParams) {
+	params.Worktree = flags.GetBoolOrDefault("worktree", false)
+}
@@ -0,0 +1,103 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"preq/internal/cli/paramutils"
+	"preq/internal/cli/utils"
+	"preq/internal/configutils"
+	"preq/internal/gitutils"
+	"preq/internal/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+var ErrWorktreeRequired = errors.New(
+	"only worktree reviews are supported, use --worktree or preq checkout to switch the working copy",
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review ID",
+		Short: "Review a pull request locally",
+		Long: `Checks out the source commit of a pull request in a separate git worktree below
+general.worktreeDir and prints its path, e.g. cd "$(preq review 7 --worktree)"`,
+		Args: cobra.ExactArgs(1),
+		Run:  utils.RunCommandWrapper(runCmd),
+	}
+
+	cmd.Flags().Bool("worktree", false, "Check out the pull request in a separate worktree")
+
+	return cmd
+}
+
+func runCmd(cmd *cobra.Command, args []string) error {
+	cmdArgs := parseArgs(args)
+	params := &reviewCmdParams{}
+	fillFlagReviewCmdParams(paramutils.NewFlagRepo(cmd.Flags()), params)
+	if !params.Worktree {
+		return ErrWorktreeRequired
+	}
+
+	path, err := paramutils.GetRepoPath(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	config, err := configutils.LoadConfigForPath(path)
+	if err != nil {
+		return err
+	}
+
+	dir, err := configutils.WorktreeDir(config)
+	if err != nil {
+		return err
+	}
+
+	cl, repoParams, err := paramutils.GetClientAndRepoParams(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	utils.SafelyWriteVisitToState(cmd.Flags(), repoParams)
+
+	return execute(cl, cmdArgs, &client.Repository{
+		Provider: repoParams.Provider,
+		Name:     repoParams.Name,
+	}, path, dir, os.Stdout, os.Stderr)
+}
+
+var addPullRequestWorktree = gitutils.AddPullRequestWorktree
+
+// execute creates the worktree and prints its path to w, so it can be used
+// by scripts, the messages are written to errW
+func execute(
+	c client.Client,
+	args *cmdArgs,
+	repo *client.Repository,
+	path string,
+	dir string,
+	w io.Writer,
+	errW io.Writer,
+) error {
+	pr, err := c.GetPullRequestInfo(&client.ApproveOptions{
+		Repository: repo,
+		ID:         args.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	worktree := gitutils.WorktreePath(dir, repo, pr.ID)
+	err = addPullRequestWorktree(path, worktree, repo, pr)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(errW, "Checked out pull request #%s %s\n", pr.ID, pr.Title)
+	fmt.Fprintln(w, worktree)
+
+	return nil
+}
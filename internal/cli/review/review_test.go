@@ -0,0 +1,56 @@
+package review
+
+import (
+	"bytes"
+	"errors"
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPullRequestClient struct {
+	client.MockClient
+	pr *client.PullRequest
+}
+
+func (c *mockPullRequestClient) GetPullRequestInfo(o *client.ApproveOptions) (*client.PullRequest, error) {
+	return c.pr, c.ErrorValue
+}
+
+func Test_execute(t *testing.T) {
+	repo := &client.Repository{Provider: client.RepositoryProviderEnum.GITHUB, Name: "owner/repo"}
+	pr := &client.PullRequest{ID: "7", Title: "Feature"}
+
+	defer func(f func(string, string, *client.Repository, *client.PullRequest) error) {
+		addPullRequestWorktree = f
+	}(addPullRequestWorktree)
+
+	t.Run("creates the worktree and prints its path", func(t *testing.T) {
+		var repoPath, worktree string
+		addPullRequestWorktree = func(p string, w string, r *client.Repository, v *client.PullRequest) error {
+			repoPath, worktree = p, w
+			return nil
+		}
+
+		w, errW := &bytes.Buffer{}, &bytes.Buffer{}
+		err := execute(&mockPullRequestClient{pr: pr}, &cmdArgs{ID: "7"}, repo, "/src/repo", "/worktrees", w, errW)
+		assert.NoError(t, err)
+		assert.Equal(t, "/src/repo", repoPath)
+		assert.Equal(t, "/worktrees/github/owner/repo/pr-7", worktree)
+		assert.Equal(t, "/worktrees/github/owner/repo/pr-7\n", w.String())
+		assert.Contains(t, errW.String(), "#7 Feature")
+	})
+
+	t.Run("returns the worktree error", func(t *testing.T) {
+		vErr := errors.New("fetch failed")
+		addPullRequestWorktree = func(string, string, *client.Repository, *client.PullRequest) error {
+			return vErr
+		}
+
+		w := &bytes.Buffer{}
+		err := execute(&mockPullRequestClient{pr: pr}, &cmdArgs{ID: "7"}, repo, "/src/repo", "/worktrees", w, &bytes.Buffer{})
+		assert.ErrorIs(t, err, vErr)
+		assert.Empty(t, w.String())
+	})
+}
@@ -4,25 +4,34 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"path"
 	approvecmd "preq/internal/cli/approve"
+	authcmd "preq/internal/cli/auth"
+	checkoutcmd "preq/internal/cli/checkout"
+	configcmd "preq/internal/cli/config"
 	createcmd "preq/internal/cli/create"
 	declinecmd "preq/internal/cli/decline"
+	inboxcmd "preq/internal/cli/inbox"
 	listcmd "preq/internal/cli/list"
+	mergecmd "preq/internal/cli/merge"
 	opencmd "preq/internal/cli/open"
 	"preq/internal/cli/paramutils"
+	readycmd "preq/internal/cli/ready"
+	reviewcmd "preq/internal/cli/review"
+	updatecmd "preq/internal/cli/update"
 	"preq/internal/cli/utils"
+	worktreecmd "preq/internal/cli/worktree"
+	"preq/internal/configutils"
 	"preq/internal/gitutils"
+	"preq/internal/logutils"
 	"preq/internal/persistance"
 	"preq/internal/pkg/client"
 	"preq/internal/tui"
-	"time"
 
-	"github.com/mitchellh/go-homedir"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 )
 
 var (
@@ -31,11 +40,16 @@ var (
 	date    = "unknown"
 )
 
+var logFile io.Closer
+
 var rootCmd = &cobra.Command{
 	Use:     "preq",
 	Short:   "Pull request manager",
 	Long:    "TUI utility for managing pull requests.",
 	Version: fmt.Sprintf("%v, commit %v, built at %v", version, commit, date),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setupLogging(cmd)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		params := &paramutils.RepositoryParams{}
 		flags := &paramutils.PFlagSetWrapper{Flags: cmd.Flags()}
@@ -113,55 +127,44 @@ var rootCmd = &cobra.Command{
 	},
 }
 
-func Execute() {
-	dir, err := homedir.Expand(`~/.local/state/preq`)
-	if err != nil {
-		panic(err)
-	}
+// setupLogging configures the logger from the global configuration, the
+// flags override the configured values
+func setupLogging(cmd *cobra.Command) error {
+	// Nothing is logged until the log file is known
+	zerolog.SetGlobalLevel(zerolog.Disabled)
 
-	os.MkdirAll(dir, 0o700)
-	file, err := os.OpenFile(
-		path.Join(dir, "app.log"),
-		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
-		0o0600,
-	)
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to open log file")
+	config, err := configutils.DefaultConfig()
+	if err != nil || config == nil {
+		config = viper.New()
 	}
-	defer file.Close()
-
-	mw := io.MultiWriter(
-		// TODO: Write to a in memory file writer? To show errors (logs) in the app, or maybe just use a hook or something?
-		zerolog.ConsoleWriter{
-			Out:        file,
-			TimeFormat: time.RFC3339,
-		},
-	)
-
-	log.Logger = zerolog.New(mw).With().Timestamp().Logger()
 
-	zerolog.SetGlobalLevel(zerolog.ErrorLevel)
-
-	// TODO: Read the log level value from the config
-	levelInput := "debug"
-	level, err := zerolog.ParseLevel(levelInput)
+	flags := paramutils.NewFlagRepo(cmd.Flags())
+	logFile, err = logutils.Setup(&logutils.Options{
+		Level: flags.GetStringOrDefault("log-level", config.GetString("general.logLevel")),
+		File:  flags.GetStringOrDefault("log-file", config.GetString("general.logFile")),
+	})
 	if err != nil {
-		log.Error().
-			Msgf("unknown log level '%v', reverting to default error level", levelInput)
-	} else {
-		zerolog.SetGlobalLevel(level)
+		return fmt.Errorf("failed to open log file: %w", err)
 	}
 
+	return nil
+}
+
+func Execute() {
 	rootCmd.AddCommand(createcmd.New())
 	rootCmd.AddCommand(approvecmd.New())
 	rootCmd.AddCommand(declinecmd.New())
 	rootCmd.AddCommand(listcmd.New())
 	rootCmd.AddCommand(opencmd.New())
-
-	//! Update command is not currently implemented
-	// rootCmd.AddCommand(updatecmd.New())
-
-	// TODO: Create config command?
+	rootCmd.AddCommand(mergecmd.New())
+	rootCmd.AddCommand(updatecmd.New())
+	rootCmd.AddCommand(readycmd.New())
+	rootCmd.AddCommand(authcmd.New())
+	rootCmd.AddCommand(configcmd.New())
+	rootCmd.AddCommand(inboxcmd.New())
+	rootCmd.AddCommand(checkoutcmd.New())
+	rootCmd.AddCommand(reviewcmd.New())
+	rootCmd.AddCommand(worktreecmd.New())
 
 	rootCmd.Flags().
 		BoolP("global", "g", false, "Show information about all known (previously visited) repositories.")
@@ -173,5 +176,14 @@ func Execute() {
 		StringP("provider", "p", "", "repository host, values - (bitbucket)")
 	rootCmd.MarkFlagsRequiredTogether("repository", "provider")
 
+	rootCmd.PersistentFlags().
+		String("log-level", "", "log level, values - (trace, debug, info, warn, error, fatal, panic, disabled)")
+	rootCmd.PersistentFlags().
+		String("log-file", "", "log file path, defaults to ~/.local/state/preq/app.log")
+
 	rootCmd.Execute()
+
+	if logFile != nil {
+		logFile.Close()
+	}
 }
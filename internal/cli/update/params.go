@@ -1,48 +1,80 @@
 package update
 
 import (
-	"preq/internal/configutils"
-	"preq/internal/errcodes"
-	"strings"
-
-	"github.com/spf13/cobra"
+	"errors"
+	"preq/internal/cli/paramutils"
 )
 
+type cmdArgs struct {
+	ID string
+}
+
 type updateCmdParams struct {
-	Provider                 string
-	Repository               string
-	EnableDraft              bool
-	DisableDraft             bool
-	EnableCloseSourceBranch  bool
-	DisableCloseSourceBranch bool
+	Title           *string
+	Description     *string
+	Destination     *string
+	Draft           *bool
+	AddReviewers    []string
+	RemoveReviewers []string
+}
+
+func parseArgs(args []string) *cmdArgs {
+	return &cmdArgs{ID: paramutils.ParseIDArg(args)}
+}
+
+func changedString(flags paramutils.FlagRepo, flag string) *string {
+	if !flags.Changed(flag) {
+		return nil
+	}
+
+	v := flags.GetStringOrDefault(flag, "")
+	return &v
 }
 
 func fillFlagUpdateCmdParams(
-	cmd *cobra.Command,
+	flags paramutils.FlagRepo,
 	params *updateCmdParams,
 ) error {
-	var (
-		repo = configutils.GetStringFlagOrDefault(
-			cmd.Flags(),
-			"repository",
-			"",
-		)
-		provider = configutils.GetStringFlagOrDefault(
-			cmd.Flags(),
-			"provider",
-			"",
-		)
+	params.Title = changedString(flags, "title")
+	params.Description = changedString(flags, "description")
+	params.Destination = changedString(flags, "destination")
+	params.AddReviewers = flags.GetStringSliceOrDefault(
+		"add-reviewer",
+		params.AddReviewers,
 	)
+	params.RemoveReviewers = flags.GetStringSliceOrDefault(
+		"remove-reviewer",
+		params.RemoveReviewers,
+	)
+
+	if flags.Changed("draft") && flags.Changed("ready") {
+		return errors.New("draft and ready flags cannot be used together")
+	}
+
+	if flags.Changed("draft") {
+		draft := flags.GetBoolOrDefault("draft", true)
+		params.Draft = &draft
+	} else if flags.Changed("ready") {
+		draft := !flags.GetBoolOrDefault("ready", true)
+		params.Draft = &draft
+	}
 
-	if repo != "" && provider != "" {
-		v := strings.Split(repo, "/")
-		if len(v) != 2 || v[0] == "" || v[1] == "" {
-			return errcodes.ErrRepositoryMustBeInFormOwnerRepo
-		}
+	if params.Title != nil && *params.Title == "" {
+		return errors.New("title cannot be empty")
+	}
 
-		params.Provider = provider
-		params.Repository = repo
+	if params.Destination != nil && *params.Destination == "" {
+		return errors.New("destination cannot be empty")
 	}
 
 	return nil
 }
+
+func (p *updateCmdParams) IsEmpty() bool {
+	return p.Title == nil &&
+		p.Description == nil &&
+		p.Destination == nil &&
+		p.Draft == nil &&
+		len(p.AddReviewers) == 0 &&
+		len(p.RemoveReviewers) == 0
+}
@@ -0,0 +1,45 @@
+package update
+
+import (
+	"preq/internal/cli/paramutils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_fillFlagUpdateCmdParams(t *testing.T) {
+	t.Run("leaves unchanged flags empty", func(t *testing.T) {
+		params := &updateCmdParams{}
+		err := fillFlagUpdateCmdParams(&paramutils.MockPreqFlagSet{
+			StringMap: map[string]interface{}{"title": "new title"},
+		}, params)
+		assert.NoError(t, err)
+		assert.Equal(t, "new title", *params.Title)
+		assert.Nil(t, params.Description)
+		assert.Nil(t, params.Draft)
+		assert.False(t, params.IsEmpty())
+	})
+
+	t.Run("marks the pull request ready", func(t *testing.T) {
+		params := &updateCmdParams{}
+		err := fillFlagUpdateCmdParams(&paramutils.MockPreqFlagSet{
+			StringMap: map[string]interface{}{"ready": true},
+		}, params)
+		assert.NoError(t, err)
+		assert.False(t, *params.Draft)
+	})
+
+	t.Run("fails when both draft and ready are set", func(t *testing.T) {
+		err := fillFlagUpdateCmdParams(&paramutils.MockPreqFlagSet{
+			StringMap: map[string]interface{}{"draft": true, "ready": true},
+		}, &updateCmdParams{})
+		assert.Error(t, err)
+	})
+
+	t.Run("fails on an empty title", func(t *testing.T) {
+		err := fillFlagUpdateCmdParams(&paramutils.MockPreqFlagSet{
+			StringMap: map[string]interface{}{"title": ""},
+		}, &updateCmdParams{})
+		assert.Error(t, err)
+	})
+}
@@ -1,82 +1,136 @@
 package update
 
 import (
+	"errors"
 	"fmt"
+	"preq/internal/cli/paramutils"
+	"preq/internal/cli/utils"
+	"preq/internal/clientutils"
+	"preq/internal/domain/pullrequest"
+	"preq/internal/pkg/client"
 
 	"github.com/spf13/cobra"
 )
 
-func runCmd(cmd *cobra.Command, args []string) {
-	id := ""
-	if len(args) > 0 {
-		id = args[0]
-	}
-	fmt.Println(id)
-	fmt.Println(cmd.Flags().Changed("draft"))
-
-	// TODO: Implement update command
-	// cl, err := clientutils.ClientFactory{}.DefaultClient()
-	// if err != nil {
-	// 	fmt.Println(err)
-	// 	os.Exit(systemcodes.ErrorCodeGeneric)
-	// }
-
-	// params := &updateCmdParams{}
-	// fillDefaultUpdateCmdParams(params)
-	// err = fillFlagUpdateCmdParams(cmd, params)
-	// if err != nil {
-	// 	fmt.Println(err)
-	// 	os.Exit(3)
-	// }
-
-	// r, err := client.NewRepositoryFromOptions(&client.RepositoryOptions{
-	// 	Provider:           params.Provider,
-	// 	FullRepositoryName: params.Repository,
-	// })
-	// if err != nil {
-	// 	fmt.Println(err)
-	// 	os.Exit(systemcodes.ErrorCodeGeneric)
-	// }
-
-	// if id != "" {
-	// 	_, err = cl.ApprovePullRequest(&client.ApprovePullRequestOptions{
-	// 		Repository: r,
-	// 		ID:         args[0],
-	// 	})
-	// 	if err != nil {
-	// 		fmt.Println(err)
-	// 		os.Exit(systemcodes.ErrorCodeGeneric)
-	// 	}
-	// } else {
-	// 	prList, err := cl.GetPullRequests(&client.GetPullRequestsOptions{
-	// 		Repository: r,
-	// 		State:      client.PullRequestState_OPEN,
-	// 	})
-	// 	if err != nil {
-	// 		fmt.Println(err)
-	// 		os.Exit(systemcodes.ErrorCodeGeneric)
-	// 	}
-
-	// 	selectedPRs := promptPullRequestMultiSelect(prList)
-	// 	processPullRequestMap(selectedPRs, cl, r, approvePR, func(msg interface{}) string {
-	// 		m := msg.(approveResponse)
-	// 		return fmt.Sprintf("Approving #%s... %s\n", m.ID, m.Status)
-	// 	})
-	// }
-}
-
 func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "update [ID]",
 		Aliases: []string{"up"},
 		Short:   "Update pull request",
-		Long:    `Updates a pull requests on the web service hosting your origin repository`,
-		Args:    cobra.MaximumNArgs(1),
-		Run:     runCmd,
+		Long:    `Updates a pull request on the web service hosting your origin repository`,
+		Args:    cobra.ExactArgs(1),
+		Run:     utils.RunCommandWrapper(runCmd),
 	}
 
-	cmd.Flags().Bool("draft", false, "repository host, values - (bitbucket)")
-	cmd.Flags().Bool("close", false, "repository host, values - (bitbucket)")
+	cmd.Flags().StringP("title", "t", "", "the new title of the pull request")
+	cmd.Flags().String("description", "", "the new description of the pull request")
+	cmd.Flags().
+		StringP("destination", "d", "", "the new destination branch of the pull request")
+	cmd.Flags().
+		StringSlice("add-reviewer", []string{}, "add a reviewer to the pull request (can be repeated)")
+	cmd.Flags().
+		StringSlice("remove-reviewer", []string{}, "remove a reviewer from the pull request (can be repeated)")
+	cmd.Flags().Bool("draft", false, "mark the pull request as draft")
+	cmd.Flags().Bool("ready", false, "mark the pull request as ready for review")
+	cmd.MarkFlagsMutuallyExclusive("draft", "ready")
 
 	return cmd
 }
+
+func runCmd(cmd *cobra.Command, args []string) error {
+	cmdArgs := parseArgs(args)
+
+	params := &updateCmdParams{}
+	err := fillFlagUpdateCmdParams(paramutils.NewFlagRepo(cmd.Flags()), params)
+	if err != nil {
+		return err
+	}
+
+	cl, repoParams, err := paramutils.GetClientAndRepoParams(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	utils.SafelyWriteVisitToState(cmd.Flags(), repoParams)
+
+	return execute(cl, cmdArgs, params, &client.Repository{
+		Provider: repoParams.Provider,
+		Name:     repoParams.Name,
+	})
+}
+
+type updaterAdapter struct {
+	Client client.Client
+}
+
+func (ua *updaterAdapter) Update(
+	o *pullrequest.UpdateOptions,
+) (*pullrequest.Entity, error) {
+	pr, err := ua.Client.UpdatePullRequest(&client.UpdatePullRequestOptions{
+		Repository:      o.Repository,
+		ID:              o.ID,
+		Title:           o.Title,
+		Description:     o.Description,
+		Destination:     o.Destination,
+		Draft:           o.Draft,
+		AddReviewers:    o.AddReviewers,
+		RemoveReviewers: o.RemoveReviewers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pullrequest.Entity{
+		Destination: pr.Destination.Name,
+		Source:      pr.Source.Name,
+		Title:       pr.Title,
+		URL:         pr.URL,
+	}, nil
+}
+
+func execute(
+	c client.Client,
+	args *cmdArgs,
+	params *updateCmdParams,
+	repo *client.Repository,
+) error {
+	if args.ID == "" {
+		return errors.New("missing pull request ID")
+	}
+
+	if params.IsEmpty() {
+		return errors.New("nothing to update")
+	}
+
+	addReviewers, err := clientutils.ResolveReviewers(c, repo, params.AddReviewers)
+	if err != nil {
+		return err
+	}
+
+	removeReviewers, err := clientutils.ResolveReviewers(c, repo, params.RemoveReviewers)
+	if err != nil {
+		return err
+	}
+
+	service := pullrequest.NewUpdateService(&updaterAdapter{Client: c})
+	pr, err := service.Update(&pullrequest.UpdateOptions{
+		Repository:      repo,
+		ID:              args.ID,
+		Title:           params.Title,
+		Description:     params.Description,
+		Destination:     params.Destination,
+		Draft:           params.Draft,
+		AddReviewers:    addReviewers,
+		RemoveReviewers: removeReviewers,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated pull request #%s\n", args.ID)
+	if pr.URL != "" {
+		fmt.Println(pr.URL)
+	}
+
+	return nil
+}
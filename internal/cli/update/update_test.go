@@ -0,0 +1,47 @@
+package update
+
+import (
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// updateClient records the options of the updated pull request
+type updateClient struct {
+	*client.MockClient
+	options *client.UpdatePullRequestOptions
+}
+
+func (c *updateClient) UpdatePullRequest(
+	o *client.UpdatePullRequestOptions,
+) (*client.PullRequest, error) {
+	c.options = o
+	return c.MockClient.UpdatePullRequest(o)
+}
+
+func Test_execute(t *testing.T) {
+	repo := &client.Repository{Name: "owner/repo"}
+
+	t.Run("resolves the reviewers", func(t *testing.T) {
+		c := &updateClient{MockClient: &client.MockClient{
+			UsersValue: []*client.User{{ID: "{1}", Username: "john"}},
+		}}
+		err := execute(c, &cmdArgs{ID: "1"}, &updateCmdParams{
+			AddReviewers:    []string{"John"},
+			RemoveReviewers: []string{"john"},
+		}, repo)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"{1}"}, c.options.AddReviewers)
+		assert.Equal(t, []string{"{1}"}, c.options.RemoveReviewers)
+	})
+
+	t.Run("fails on unknown reviewers", func(t *testing.T) {
+		c := &updateClient{MockClient: &client.MockClient{}}
+		err := execute(c, &cmdArgs{ID: "1"}, &updateCmdParams{
+			AddReviewers: []string{"john"},
+		}, repo)
+		assert.Error(t, err)
+		assert.Nil(t, c.options)
+	})
+}
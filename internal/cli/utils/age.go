@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatAge formats the duration in its largest unit, e.g. 3d, 5h or 12m
+func FormatAge(d time.Duration) string {
+	switch {
+	case d >= 7*24*time.Hour:
+		return fmt.Sprintf("%dw", int(d/(7*24*time.Hour)))
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+
+	return "now"
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"preq/internal/clientutils"
+	"preq/internal/configutils"
+	"preq/internal/persistance"
+	"preq/internal/pkg/client"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// visitedConcurrency limits the number of repositories requested at once
+const visitedConcurrency = 8
+
+var ErrRepositoryTimeout = errors.New("timed out")
+
+// VisitedRepoResult is the outcome of a request to a visited repository
+type VisitedRepoResult struct {
+	Repository   *persistance.PersistanceRepoInfo
+	PullRequests []*client.PullRequest
+	Err          error
+}
+
+func (r *VisitedRepoResult) Name() string {
+	return fmt.Sprintf("%s (%s)", r.Repository.Name, r.Repository.Provider)
+}
+
+type (
+	VisitedRepoClientFactory func(*persistance.PersistanceRepoInfo) (client.Client, error)
+	VisitedRepoFunc          func(client.Client, *client.Repository) ([]*client.PullRequest, error)
+)
+
+// NewVisitedRepoClient creates the client of a visited repository with its
+// local configuration merged into the global one
+func NewVisitedRepoClient(info *persistance.PersistanceRepoInfo) (client.Client, error) {
+	var (
+		config *viper.Viper
+		err    error
+	)
+	if info.Path != "" {
+		config, err = configutils.LoadConfigForPath(info.Path)
+	} else {
+		config, err = configutils.DefaultConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = viper.New()
+	}
+
+	return clientutils.ClientFactory{}.NewClient(
+		client.RepositoryProvider(info.Provider),
+		config,
+	)
+}
+
+func runVisitedRepo(
+	info *persistance.PersistanceRepoInfo,
+	newClient VisitedRepoClientFactory,
+	fn VisitedRepoFunc,
+) ([]*client.PullRequest, error) {
+	c, err := newClient(info)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := client.NewRepositoryFromOptions(&client.RepositoryOptions{
+		Provider: client.RepositoryProvider(info.Provider),
+		Name:     info.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fn(c, repo)
+}
+
+// ForEachVisitedRepo calls fn for the repositories concurrently, the results
+// are in the order of the repositories. A repository which does not respond
+// within the timeout is reported as failed, its request is left to finish
+// in the background since the clients cannot be cancelled. The request keeps
+// its slot of the concurrency limit until it finishes.
+func ForEachVisitedRepo(
+	ctx context.Context,
+	repos []*persistance.PersistanceRepoInfo,
+	timeout time.Duration,
+	newClient VisitedRepoClientFactory,
+	fn VisitedRepoFunc,
+) []*VisitedRepoResult {
+	results := make([]*VisitedRepoResult, len(repos))
+	sem := make(chan struct{}, visitedConcurrency)
+	wg := sync.WaitGroup{}
+
+	for i, info := range repos {
+		results[i] = &VisitedRepoResult{Repository: info}
+
+		wg.Add(1)
+		go func(r *VisitedRepoResult) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				r.Err = ctx.Err()
+				return
+			}
+
+			repoCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			done := make(chan struct{})
+			var (
+				prs []*client.PullRequest
+				err error
+			)
+			go func() {
+				defer func() { <-sem }()
+				defer close(done)
+				prs, err = runVisitedRepo(r.Repository, newClient, fn)
+			}()
+
+			select {
+			case <-done:
+				r.PullRequests, r.Err = prs, err
+			case <-repoCtx.Done():
+				r.Err = ErrRepositoryTimeout
+				if errors.Is(repoCtx.Err(), context.Canceled) {
+					r.Err = repoCtx.Err()
+				}
+			}
+		}(results[i])
+	}
+	wg.Wait()
+
+	return results
+}
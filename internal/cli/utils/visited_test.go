@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"context"
+	"preq/internal/persistance"
+	"preq/internal/pkg/client"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEachVisitedRepo(t *testing.T) {
+	t.Run("keeps the limit for the timed out repositories", func(t *testing.T) {
+		repos := []*persistance.PersistanceRepoInfo{}
+		for i := 0; i < visitedConcurrency*2; i++ {
+			repos = append(repos, &persistance.PersistanceRepoInfo{Provider: "github", Name: "owner/repo"})
+		}
+
+		var (
+			mu      sync.Mutex
+			running int
+			maxRun  int
+		)
+		release := make(chan struct{})
+		defer close(release)
+
+		newClient := func(*persistance.PersistanceRepoInfo) (client.Client, error) {
+			return &client.MockClient{}, nil
+		}
+		fn := func(client.Client, *client.Repository) ([]*client.PullRequest, error) {
+			mu.Lock()
+			running++
+			if running > maxRun {
+				maxRun = running
+			}
+			mu.Unlock()
+
+			<-release
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil, nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		results := ForEachVisitedRepo(ctx, repos, 10*time.Millisecond, newClient, fn)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, visitedConcurrency, maxRun)
+		for _, r := range results {
+			assert.Error(t, r.Err)
+		}
+	})
+}
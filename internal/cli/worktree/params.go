@@ -0,0 +1,15 @@
+package worktree
+
+import (
+	"preq/internal/cli/paramutils"
+)
+
+type pruneCmdParams struct {
+	DryRun bool
+	Force  bool
+}
+
+func fillFlagPruneCmdParams(flags paramutils.FlagRepo, params *pruneCmdParams) {
+	params.DryRun = flags.GetBoolOrDefault("dry-run", false)
+	params.Force = flags.GetBoolOrDefault("force", false)
+}
@@ -0,0 +1,132 @@
+package worktree
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"preq/internal/cli/paramutils"
+	"preq/internal/cli/utils"
+	"preq/internal/configutils"
+	"preq/internal/gitutils"
+	"preq/internal/pkg/client"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "worktree",
+		Short: "Manage the review worktrees",
+		Long:  `Manages the worktrees created by preq review --worktree`,
+	}
+
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove the worktrees of merged or declined pull requests",
+		Long:  `Removes the review worktrees of the repository whose pull requests are merged or declined`,
+		Args:  cobra.NoArgs,
+		Run:   utils.RunCommandWrapper(runPruneCmd),
+	}
+	pruneCmd.Flags().Bool("dry-run", false, "Only print the worktrees which would be removed")
+	pruneCmd.Flags().Bool("force", false, "Remove the worktrees with uncommitted changes as well")
+
+	cmd.AddCommand(pruneCmd)
+
+	return cmd
+}
+
+func runPruneCmd(cmd *cobra.Command, args []string) error {
+	params := &pruneCmdParams{}
+	fillFlagPruneCmdParams(paramutils.NewFlagRepo(cmd.Flags()), params)
+
+	path, err := paramutils.GetRepoPath(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	config, err := configutils.LoadConfigForPath(path)
+	if err != nil {
+		return err
+	}
+
+	dir, err := configutils.WorktreeDir(config)
+	if err != nil {
+		return err
+	}
+
+	cl, repoParams, err := paramutils.GetClientAndRepoParams(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	return executePrune(cl, &client.Repository{
+		Provider: repoParams.Provider,
+		Name:     repoParams.Name,
+	}, path, dir, params, os.Stdout)
+}
+
+var (
+	listWorktrees  = gitutils.ListWorktrees
+	removeWorktree = gitutils.RemoveWorktree
+)
+
+func isClosed(state client.PullRequestState) bool {
+	return state == client.PullRequestState_MERGED || state == client.PullRequestState_DECLINED
+}
+
+// executePrune removes the worktrees of the closed pull requests, the
+// worktrees which cannot be checked or removed are reported and kept
+func executePrune(
+	c client.Client,
+	repo *client.Repository,
+	path string,
+	dir string,
+	params *pruneCmdParams,
+	w io.Writer,
+) error {
+	worktrees, err := listWorktrees(path)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, wt := range worktrees {
+		id, ok := gitutils.WorktreePullRequestID(dir, repo, wt.Path)
+		if !ok {
+			continue
+		}
+
+		pr, err := c.GetPullRequestInfo(&client.ApproveOptions{Repository: repo, ID: id})
+		if err != nil {
+			failed++
+			fmt.Fprintf(w, "Kept %s: %s\n", wt.Path, err)
+			continue
+		}
+
+		if !isClosed(pr.State) {
+			continue
+		}
+
+		state := strings.ToLower(string(pr.State))
+		if params.DryRun {
+			fmt.Fprintf(w, "Would remove %s, #%s is %s\n", wt.Path, id, state)
+			continue
+		}
+
+		err = removeWorktree(path, wt.Path, params.Force)
+		if err != nil {
+			failed++
+			fmt.Fprintf(w, "Kept %s: %s\n", wt.Path, err)
+			continue
+		}
+
+		fmt.Fprintf(w, "Removed %s, #%s is %s\n", wt.Path, id, state)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to prune %d worktrees", failed)
+	}
+
+	return nil
+}
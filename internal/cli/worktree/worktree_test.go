@@ -0,0 +1,98 @@
+package worktree
+
+import (
+	"bytes"
+	"errors"
+	"preq/internal/gitutils"
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStateClient struct {
+	client.MockClient
+	states map[string]client.PullRequestState
+}
+
+func (c *mockStateClient) GetPullRequestInfo(o *client.ApproveOptions) (*client.PullRequest, error) {
+	state, ok := c.states[o.ID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	return &client.PullRequest{ID: o.ID, State: state}, nil
+}
+
+func Test_executePrune(t *testing.T) {
+	repo := &client.Repository{Provider: client.RepositoryProviderEnum.GITHUB, Name: "owner/repo"}
+	c := &mockStateClient{states: map[string]client.PullRequestState{
+		"1": client.PullRequestState_MERGED,
+		"2": client.PullRequestState_OPEN,
+		"3": client.PullRequestState_DECLINED,
+	}}
+
+	defer func(f func(string) ([]*gitutils.Worktree, error)) { listWorktrees = f }(listWorktrees)
+	listWorktrees = func(string) ([]*gitutils.Worktree, error) {
+		return []*gitutils.Worktree{
+			{Path: "/worktrees/github/owner/repo/pr-1"},
+			{Path: "/worktrees/github/owner/repo/pr-2"},
+			{Path: "/worktrees/github/owner/repo/pr-3"},
+			{Path: "/worktrees/github/owner/repo/pr-4"},
+			{Path: "/elsewhere/pr-1"},
+		}, nil
+	}
+
+	defer func(f func(string, string, bool) error) { removeWorktree = f }(removeWorktree)
+
+	t.Run("removes the worktrees of closed pull requests", func(t *testing.T) {
+		removed := []string{}
+		removeWorktree = func(dir string, path string, force bool) error {
+			removed = append(removed, path)
+			return nil
+		}
+
+		w := &bytes.Buffer{}
+		err := executePrune(c, repo, "/src/repo", "/worktrees", &pruneCmdParams{}, w)
+		assert.EqualError(t, err, "failed to prune 1 worktrees")
+		assert.Equal(t, []string{
+			"/worktrees/github/owner/repo/pr-1",
+			"/worktrees/github/owner/repo/pr-3",
+		}, removed)
+		assert.Contains(t, w.String(), "Removed /worktrees/github/owner/repo/pr-1, #1 is merged")
+		assert.Contains(t, w.String(), "Kept /worktrees/github/owner/repo/pr-4: not found")
+	})
+
+	t.Run("only prints with dry run", func(t *testing.T) {
+		removeWorktree = func(string, string, bool) error {
+			t.Fatal("removed a worktree")
+			return nil
+		}
+
+		w := &bytes.Buffer{}
+		_ = executePrune(c, repo, "/src/repo", "/worktrees", &pruneCmdParams{DryRun: true}, w)
+		assert.Contains(t, w.String(), "Would remove /worktrees/github/owner/repo/pr-3, #3 is declined")
+	})
+	t.Run("prunes the worktrees of a Bitbucket repository", func(t *testing.T) {
+		repo := &client.Repository{Provider: client.RepositoryProviderEnum.BITBUCKET, Name: "owner/repo"}
+		listWorktrees = func(string) ([]*gitutils.Worktree, error) {
+			return []*gitutils.Worktree{
+				{Path: "/worktrees/bitbucket/owner/repo/pr-1"},
+				{Path: "/worktrees/bitbucket/owner/repo/pr-2"},
+				{Path: "/worktrees/github/owner/repo/pr-3"},
+			}, nil
+		}
+
+		removed := []string{}
+		removeWorktree = func(dir string, path string, force bool) error {
+			removed = append(removed, path)
+			return nil
+		}
+
+		w := &bytes.Buffer{}
+		err := executePrune(c, repo, "/src/repo", "/worktrees", &pruneCmdParams{}, w)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/worktrees/bitbucket/owner/repo/pr-1"}, removed)
+		assert.Equal(t, "Removed /worktrees/bitbucket/owner/repo/pr-1, #1 is merged\n", w.String())
+	})
+}
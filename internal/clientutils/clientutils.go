@@ -3,9 +3,12 @@ package clientutils
 import (
 	"errors"
 	"fmt"
+	"preq/internal/credentialutils"
 	"preq/internal/pkg/bitbucket"
+	"preq/internal/pkg/bitbucketserver"
 	"preq/internal/pkg/client"
 	"preq/internal/pkg/github"
+	"preq/internal/pkg/gitlab"
 
 	"github.com/spf13/viper"
 )
@@ -13,55 +16,86 @@ import (
 type ClientFactory struct{}
 
 func (cf ClientFactory) NewClient(provider client.RepositoryProvider, config *viper.Viper) (client.Client, error) {
+	c, credential, err := newClientForRepository(provider, config, config.GetString("default.repository"))
+	if err != nil {
+		return nil, err
+	}
+
+	return withCurrentUserCache(c, accountKey(provider, config, credential)), nil
+}
+
+func newClientForRepository(
+	provider client.RepositoryProvider,
+	config *viper.Viper,
+	repository string,
+) (client.Client, *credentialutils.Credential, error) {
 	switch provider {
 	case client.RepositoryProviderEnum.BITBUCKET:
-		username := config.GetString("bitbucket.username")
-		if username == "" {
-			return nil, fmt.Errorf("missing username")
+		credential, err := credentialutils.Resolve(provider, config)
+		if err != nil {
+			return nil, nil, err
 		}
-		password := config.GetString("bitbucket.password")
-		if password == "" {
-			return nil, fmt.Errorf("missing password")
+		if credential.Username == "" {
+			return nil, nil, fmt.Errorf("missing username")
 		}
 		uuid := config.GetString("bitbucket.uuid")
-		repository := config.GetString("default.repository")
 
-		return bitbucket.NewClient(&bitbucket.ClientOptions{
-			Username:   username,
-			Password:   password,
+		c, err := bitbucket.NewClient(&bitbucket.ClientOptions{
+			Username:   credential.Username,
+			Password:   credential.Secret,
 			Uuid:       uuid,
 			Repository: repository,
 		})
+
+		return c, credential, err
 	case client.RepositoryProviderEnum.GITHUB:
-		return github.DefaultClient()
+		credential, err := credentialutils.Resolve(provider, config)
+		if err != nil {
+			return nil, nil, err
+		}
+		if credential.Username == "" {
+			return nil, nil, fmt.Errorf("missing username")
+		}
+
+		return github.New(&github.ClientOptions{
+			Username: credential.Username,
+			Token:    credential.Secret,
+			BaseURL:  config.GetString("github.baseUrl"),
+		}), credential, nil
+	case client.RepositoryProviderEnum.BITBUCKET_SERVER:
+		baseURL := config.GetString("bitbucketserver.baseUrl")
+		if baseURL == "" {
+			return nil, nil, fmt.Errorf("missing base URL")
+		}
+		credential, err := credentialutils.Resolve(provider, config)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return bitbucketserver.New(&bitbucketserver.ClientOptions{
+			Token:   credential.Secret,
+			BaseURL: baseURL,
+		}), credential, nil
+	case client.RepositoryProviderEnum.GITLAB:
+		credential, err := credentialutils.Resolve(provider, config)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return gitlab.New(&gitlab.ClientOptions{
+			Token:   credential.Secret,
+			BaseURL: config.GetString("gitlab.baseUrl"),
+		}), credential, nil
 	}
 
-	return nil, errors.New("unknown provider")
+	return nil, nil, errors.New("unknown provider")
 }
 
 func (cf ClientFactory) DefaultClientCustom(provider client.RepositoryProvider, project string) (client.Client, error) {
-	switch provider {
-	case client.RepositoryProviderEnum.BITBUCKET:
-		username := viper.GetString("bitbucket.username")
-		if username == "" {
-			return nil, fmt.Errorf("missing username")
-		}
-		password := viper.GetString("bitbucket.password")
-		if password == "" {
-			return nil, fmt.Errorf("missing password")
-		}
-		uuid := viper.GetString("bitbucket.uuid")
-		repository := project
-
-		return bitbucket.NewClient(&bitbucket.ClientOptions{
-			Username:   username,
-			Password:   password,
-			Uuid:       uuid,
-			Repository: repository,
-		})
-	case client.RepositoryProviderEnum.GITHUB:
-		return github.DefaultClient()
+	c, credential, err := newClientForRepository(provider, viper.GetViper(), project)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, errors.New("unknown provider")
+	return withCurrentUserCache(c, accountKey(provider, viper.GetViper(), credential)), nil
 }
@@ -0,0 +1,112 @@
+package clientutils
+
+import (
+	"fmt"
+	"preq/internal/credentialutils"
+	"preq/internal/persistance"
+	"preq/internal/pkg/client"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// stateMutex serializes the access to the state file, the TUI resolves the
+// current users of all repositories concurrently
+var stateMutex sync.Mutex
+
+// accountKey identifies the provider account in the state file by the
+// resolved username and the host. The accounts of the tokens without a
+// username cannot be told apart, their user is only cached in memory.
+func accountKey(
+	provider client.RepositoryProvider,
+	config *viper.Viper,
+	credential *credentialutils.Credential,
+) string {
+	if credential == nil || credential.Username == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%s:%s", provider, credentialutils.Host(provider, config), credential.Username)
+}
+
+// currentUserClient caches the authenticated user of the wrapped client
+// in memory and, when the account is known, in the persistance state
+type currentUserClient struct {
+	client.Client
+	key  string
+	mu   sync.Mutex
+	user *client.User
+}
+
+func withCurrentUserCache(c client.Client, key string) client.Client {
+	return &currentUserClient{Client: c, key: key}
+}
+
+func (c *currentUserClient) GetCurrentUser() (*client.User, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.user != nil {
+		return c.user, nil
+	}
+
+	if c.key == "" {
+		user, err := c.Client.GetCurrentUser()
+		if err != nil {
+			return nil, err
+		}
+
+		c.user = user
+		return user, nil
+	}
+
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
+	info, err := persistance.GetDefault().GetUser(c.key)
+	if err == nil {
+		c.user = &client.User{
+			ID:       info.ID,
+			Username: info.Username,
+			Name:     info.Name,
+		}
+		return c.user, nil
+	}
+
+	user, err := c.Client.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	err = persistance.GetDefault().SetUser(&persistance.PersistanceUserInfo{
+		Key:      c.key,
+		ID:       user.ID,
+		Username: user.Username,
+		Name:     user.Name,
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("unable to cache the current user")
+	}
+
+	c.user = user
+	return user, nil
+}
+
+// GetReviewRequests resolves the reviewer with the cached current user
+func (c *currentUserClient) GetReviewRequests(
+	o *client.GetReviewRequestsOptions,
+) ([]*client.PullRequest, error) {
+	if o.User == nil {
+		user, err := c.GetCurrentUser()
+		if err != nil {
+			return nil, err
+		}
+
+		options := *o
+		options.User = user
+		o = &options
+	}
+
+	return c.Client.GetReviewRequests(o)
+}
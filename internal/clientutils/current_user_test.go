@@ -0,0 +1,31 @@
+package clientutils
+
+import (
+	"preq/internal/credentialutils"
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_accountKey(t *testing.T) {
+	config := viper.New()
+	config.Set("gitlab.baseUrl", "https://gitlab.example.com/api/v4")
+
+	t.Run("uses the username and the host", func(t *testing.T) {
+		key := accountKey(client.RepositoryProviderEnum.GITHUB, config, &credentialutils.Credential{
+			Username: "octocat",
+			Secret:   "secret",
+		})
+		assert.Equal(t, "github:github.com:octocat", key)
+		assert.NotContains(t, key, "secret")
+	})
+
+	t.Run("is empty without a username", func(t *testing.T) {
+		key := accountKey(client.RepositoryProviderEnum.GITLAB, config, &credentialutils.Credential{
+			Secret: "secret",
+		})
+		assert.Equal(t, "", key)
+	})
+}
@@ -0,0 +1,66 @@
+package clientutils
+
+import (
+	"fmt"
+	"preq/internal/pkg/client"
+	"strings"
+)
+
+// ResolveReviewer finds the provider's identifier of the user, preferring
+// exact matches over a single search result
+func ResolveReviewer(
+	c client.Client,
+	repo *client.Repository,
+	name string,
+) (string, error) {
+	users, err := c.SearchUsers(&client.SearchUsersOptions{
+		Repository: repo,
+		Query:      name,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, u := range users {
+		if strings.EqualFold(u.ID, name) || strings.EqualFold(u.Username, name) {
+			return u.ID, nil
+		}
+	}
+
+	switch len(users) {
+	case 0:
+		return "", fmt.Errorf("unknown reviewer %s", name)
+	case 1:
+		return users[0].ID, nil
+	}
+
+	candidates := []string{}
+	for _, u := range users {
+		candidates = append(candidates, fmt.Sprintf("%s (%s)", u.Username, u.Name))
+	}
+
+	return "", fmt.Errorf(
+		"ambiguous reviewer %s, matches %s",
+		name,
+		strings.Join(candidates, ", "),
+	)
+}
+
+// ResolveReviewers resolves the identifiers of all the users
+func ResolveReviewers(
+	c client.Client,
+	repo *client.Repository,
+	names []string,
+) ([]string, error) {
+	ids := []string{}
+	for _, name := range names {
+		id, err := ResolveReviewer(c, repo, name)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,46 @@
+package clientutils
+
+import (
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveReviewer(t *testing.T) {
+	repo := &client.Repository{Name: "owner/repo"}
+
+	t.Run("prefers the exact match", func(t *testing.T) {
+		id, err := ResolveReviewer(&client.MockClient{
+			UsersValue: []*client.User{
+				{ID: "{1}", Username: "johnny"},
+				{ID: "{2}", Username: "john"},
+			},
+		}, repo, "John")
+		assert.NoError(t, err)
+		assert.Equal(t, "{2}", id)
+	})
+
+	t.Run("uses the only result", func(t *testing.T) {
+		id, err := ResolveReviewer(&client.MockClient{
+			UsersValue: []*client.User{{ID: "{1}", Username: "johnny"}},
+		}, repo, "john")
+		assert.NoError(t, err)
+		assert.Equal(t, "{1}", id)
+	})
+
+	t.Run("fails on ambiguous results", func(t *testing.T) {
+		_, err := ResolveReviewer(&client.MockClient{
+			UsersValue: []*client.User{
+				{ID: "{1}", Username: "johnny"},
+				{ID: "{2}", Username: "johnson"},
+			},
+		}, repo, "john")
+		assert.Error(t, err)
+	})
+
+	t.Run("fails on unknown users", func(t *testing.T) {
+		_, err := ResolveReviewer(&client.MockClient{}, repo, "john")
+		assert.Error(t, err)
+	})
+}
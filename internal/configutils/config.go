@@ -153,3 +153,17 @@ func GetStringFlagOrDefault(fs FlagSet, flag, d string) string {
 
 	return s
 }
+
+// DefaultWorktreeDir is the directory of the review worktrees unless
+// general.worktreeDir is configured
+const DefaultWorktreeDir = "~/.local/share/preq/worktrees"
+
+// WorktreeDir returns the expanded directory of the review worktrees
+func WorktreeDir(v *viper.Viper) (string, error) {
+	dir := DefaultWorktreeDir
+	if v != nil && v.GetString("general.worktreeDir") != "" {
+		dir = v.GetString("general.worktreeDir")
+	}
+
+	return homedir.Expand(dir)
+}
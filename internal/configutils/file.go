@@ -0,0 +1,284 @@
+package configutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"preq/internal/pkg/fs"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// LocalConfigFileName is the per-repository configuration file in the
+// root directory of the repository
+const LocalConfigFileName = ".preqcfg"
+
+// configFileTypes is the order in which the file types are tried for files
+// without an extension, the same as when the configuration is loaded
+var configFileTypes = []string{"yaml", "json", "toml"}
+
+// ConfigFile is a single configuration file. Unlike viper it preserves the
+// casing of the keys, so the file can be written back without changes to
+// the rest of its values.
+type ConfigFile struct {
+	Path   string
+	Type   string
+	values map[string]interface{}
+}
+
+// GlobalConfigPath returns the path of the existing global configuration
+// file, or of the default TOML file if there is none
+func GlobalConfigPath() (string, error) {
+	cfgDir, err := homedir.Expand("~/.config/preq")
+	if err != nil {
+		return "", ErrHomeDirNotFound
+	}
+
+	for _, ft := range configFileTypes {
+		f := filepath.Join(cfgDir, fmt.Sprintf("config.%s", ft))
+		if fileExists(f, fs.OS{}) == nil {
+			return f, nil
+		}
+	}
+
+	return filepath.Join(cfgDir, "config.toml"), nil
+}
+
+// LocalConfigPath returns the path of the repository configuration file
+func LocalConfigPath(repoPath string) string {
+	return filepath.Join(repoPath, LocalConfigFileName)
+}
+
+// ReadConfigFile reads the configuration file, a missing file is read as
+// an empty configuration
+func ReadConfigFile(path string) (*ConfigFile, error) {
+	f := &ConfigFile{Path: path, Type: "toml", values: map[string]interface{}{}}
+	types := configFileTypes
+	if ext := strings.TrimPrefix(filepath.Ext(path), "."); contains(configFileTypes, ext) {
+		f.Type, types = ext, []string{ext}
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(content)) == 0 {
+		return f, nil
+	}
+
+	for _, ft := range types {
+		values := map[string]interface{}{}
+		err = unmarshal(ft, content, &values)
+		if err == nil {
+			f.Type, f.values = ft, values
+			return f, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not parse %s: %w", path, err)
+}
+
+func unmarshal(fileType string, content []byte, values *map[string]interface{}) error {
+	switch fileType {
+	case "yaml":
+		return yaml.Unmarshal(content, values)
+	case "json":
+		return json.Unmarshal(content, values)
+	case "toml":
+		return toml.Unmarshal(content, values)
+	}
+
+	return fmt.Errorf("unsupported config type %s", fileType)
+}
+
+// Write writes the configuration back to its file. The file is marshalled
+// from its values, so the comments are dropped and the keys are sorted.
+func (f *ConfigFile) Write() error {
+	var (
+		content []byte
+		err     error
+	)
+
+	switch f.Type {
+	case "yaml":
+		content, err = yaml.Marshal(f.values)
+	case "json":
+		content, err = json.MarshalIndent(f.values, "", "  ")
+	case "toml":
+		content, err = toml.Marshal(f.values)
+	default:
+		err = fmt.Errorf("unsupported config type %s", f.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(f.Path), 0o700)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(f.Path, content, 0o600)
+}
+
+// findKey looks up the key in the map ignoring the case, like viper does
+func findKey(m map[string]interface{}, key string) (string, bool) {
+	if _, ok := m[key]; ok {
+		return key, true
+	}
+
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+
+	return "", false
+}
+
+// section returns the map holding the last part of the dotted key,
+// creating the missing sections if create is set
+func (f *ConfigFile) section(key string, create bool) (map[string]interface{}, string) {
+	parts := strings.Split(key, ".")
+	m := f.values
+	for _, p := range parts[:len(parts)-1] {
+		k, ok := findKey(m, p)
+		if !ok {
+			if !create {
+				return nil, ""
+			}
+
+			k = p
+			m[k] = map[string]interface{}{}
+		}
+
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			if !create {
+				return nil, ""
+			}
+
+			next = map[string]interface{}{}
+			m[k] = next
+		}
+		m = next
+	}
+
+	name := parts[len(parts)-1]
+	if k, ok := findKey(m, name); ok {
+		name = k
+	}
+
+	return m, name
+}
+
+// Get returns the value of the dotted key
+func (f *ConfigFile) Get(key string) (interface{}, bool) {
+	m, name := f.section(key, false)
+	if m == nil {
+		return nil, false
+	}
+
+	v, ok := m[name]
+	return v, ok
+}
+
+// Set sets the value of the dotted key, keeping the casing of the
+// existing keys
+func (f *ConfigFile) Set(key string, value interface{}) {
+	m, name := f.section(key, true)
+	m[name] = value
+}
+
+// Unset removes the dotted key and the sections left empty by it
+func (f *ConfigFile) Unset(key string) bool {
+	m, name := f.section(key, false)
+	if m == nil {
+		return false
+	}
+
+	if _, ok := m[name]; !ok {
+		return false
+	}
+	delete(m, name)
+
+	if i := strings.LastIndex(key, "."); i > 0 && len(m) == 0 {
+		f.Unset(key[:i])
+	}
+
+	return true
+}
+
+// Keys returns the sorted dotted keys of all the values in the file
+func (f *ConfigFile) Keys() []string {
+	keys := []string{}
+	var walk func(prefix string, m map[string]interface{})
+	walk = func(prefix string, m map[string]interface{}) {
+		for k, v := range m {
+			if sub, ok := v.(map[string]interface{}); ok {
+				walk(prefix+k+".", sub)
+				continue
+			}
+
+			keys = append(keys, prefix+k)
+		}
+	}
+	walk("", f.values)
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Viper merges the configuration files into a viper instance, the latter
+// files override the former
+func Viper(files ...*ConfigFile) (*viper.Viper, error) {
+	v := viper.New()
+	for _, f := range files {
+		err := v.MergeConfigMap(f.values)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// FormatValue formats a configuration value for the output, lists are
+// separated with commas
+func FormatValue(v interface{}) string {
+	switch value := v.(type) {
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, FormatValue(item))
+		}
+
+		return strings.Join(items, ",")
+	case []string:
+		return strings.Join(value, ",")
+	}
+
+	return fmt.Sprint(v)
+}
+
+func contains(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,70 @@
+package configutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("reads a missing file as empty", func(t *testing.T) {
+		f, err := ReadConfigFile(filepath.Join(dir, "config.yaml"))
+		assert.NoError(t, err)
+		assert.Equal(t, "yaml", f.Type)
+		assert.Empty(t, f.Keys())
+	})
+
+	t.Run("detects the type of the local file", func(t *testing.T) {
+		path := LocalConfigPath(dir)
+		os.WriteFile(path, []byte("[github]\n  baseUrl = \"https://example.com\"\n"), 0o600)
+
+		f, err := ReadConfigFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "toml", f.Type)
+		assert.Equal(t, []string{"github.baseUrl"}, f.Keys())
+	})
+
+	t.Run("fails for invalid files", func(t *testing.T) {
+		path := filepath.Join(dir, "config.json")
+		os.WriteFile(path, []byte("{"), 0o600)
+
+		_, err := ReadConfigFile(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("github:\n  baseUrl: https://example.com\n"), 0o600)
+
+	f, err := ReadConfigFile(path)
+	assert.NoError(t, err)
+
+	v, ok := f.Get("GITHUB.baseurl")
+	assert.True(t, ok)
+	assert.Equal(t, "https://example.com", v)
+
+	f.Set("github.BASEURL", "https://example.org")
+	f.Set("reviewers", []string{"alice"})
+	assert.Equal(t, []string{"github.baseUrl", "reviewers"}, f.Keys())
+
+	assert.NoError(t, f.Write())
+	f, err = ReadConfigFile(path)
+	assert.NoError(t, err)
+	v, _ = f.Get("github.baseUrl")
+	assert.Equal(t, "https://example.org", v)
+
+	assert.True(t, f.Unset("github.baseUrl"))
+	assert.False(t, f.Unset("github.baseUrl"))
+	_, ok = f.Get("github")
+	assert.False(t, ok, "the empty section is removed")
+}
+
+func TestFormatValue(t *testing.T) {
+	assert.Equal(t, "a,b", FormatValue([]interface{}{"a", "b"}))
+	assert.Equal(t, "true", FormatValue(true))
+}
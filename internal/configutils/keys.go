@@ -0,0 +1,106 @@
+package configutils
+
+import (
+	"fmt"
+	"preq/internal/pkg/client"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type KeyType int
+
+const (
+	KeyType_STRING KeyType = iota
+	KeyType_LIST
+	KeyType_BOOL
+	KeyType_INT
+)
+
+// knownKeys returns the supported configuration keys with their types
+func knownKeys() map[string]KeyType {
+	keys := map[string]KeyType{
+		"general.useNerdFontIcons": KeyType_BOOL,
+		"general.logLevel":         KeyType_STRING,
+		"general.logFile":          KeyType_STRING,
+		"general.worktreeDir":      KeyType_STRING,
+		"general.fetchOnStartup":   KeyType_BOOL,
+		"general.ignoreWhitespace": KeyType_BOOL,
+		"general.diffContextLines": KeyType_INT,
+		"default.repository":       KeyType_STRING,
+		"reviewers":                KeyType_LIST,
+		"bitbucket.password":       KeyType_STRING,
+		"bitbucket.uuid":           KeyType_STRING,
+	}
+
+	for _, p := range client.RepositoryProviders() {
+		keys[fmt.Sprintf("%s.username", p)] = KeyType_STRING
+		keys[fmt.Sprintf("%s.passwordCommand", p)] = KeyType_STRING
+		keys[fmt.Sprintf("%s.aliases", p)] = KeyType_LIST
+		if p != client.RepositoryProviderEnum.BITBUCKET {
+			keys[fmt.Sprintf("%s.token", p)] = KeyType_STRING
+			keys[fmt.Sprintf("%s.baseUrl", p)] = KeyType_STRING
+		}
+	}
+
+	return keys
+}
+
+// KnownKeys returns the sorted supported configuration keys
+func KnownKeys() []string {
+	keys := []string{}
+	for k := range knownKeys() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// LookupKey returns the canonical name and the type of the key, the keys
+// are case insensitive
+func LookupKey(key string) (string, KeyType, bool) {
+	for k, t := range knownKeys() {
+		if strings.EqualFold(k, key) {
+			return k, t, true
+		}
+	}
+
+	return "", KeyType_STRING, false
+}
+
+// ParseValue converts the command-line arguments to a value of the key's
+// type, list items can be separate arguments or separated by commas
+func ParseValue(t KeyType, args []string) (interface{}, error) {
+	switch t {
+	case KeyType_LIST:
+		items := []string{}
+		for _, arg := range args {
+			for _, item := range strings.Split(arg, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+
+		return items, nil
+	case KeyType_BOOL:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected a single boolean value")
+		}
+
+		return strconv.ParseBool(args[0])
+	case KeyType_INT:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected a single integer value")
+		}
+
+		return strconv.Atoi(args[0])
+	}
+
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected a single value")
+	}
+
+	return args[0], nil
+}
@@ -0,0 +1,40 @@
+package configutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupKey(t *testing.T) {
+	key, keyType, ok := LookupKey("github.baseurl")
+	assert.True(t, ok)
+	assert.Equal(t, "github.baseUrl", key)
+	assert.Equal(t, KeyType_STRING, keyType)
+
+	_, _, ok = LookupKey("bitbucket.baseUrl")
+	assert.False(t, ok)
+}
+
+func TestParseValue(t *testing.T) {
+	v, err := ParseValue(KeyType_LIST, []string{"a,b", "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, v)
+
+	v, err = ParseValue(KeyType_BOOL, []string{"true"})
+	assert.NoError(t, err)
+	assert.Equal(t, true, v)
+
+	_, err = ParseValue(KeyType_BOOL, []string{"yes please"})
+	assert.Error(t, err)
+
+	v, err = ParseValue(KeyType_INT, []string{"5"})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, v)
+
+	_, err = ParseValue(KeyType_INT, []string{"five"})
+	assert.Error(t, err)
+
+	_, err = ParseValue(KeyType_STRING, []string{"a", "b"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package configutils
+
+import (
+	"fmt"
+	"preq/internal/pkg/client"
+	"strings"
+)
+
+// ValidateKeys reports the keys of the file which are not supported
+func ValidateKeys(f *ConfigFile) []string {
+	problems := []string{}
+	for _, k := range f.Keys() {
+		if _, _, ok := LookupKey(k); !ok {
+			problems = append(problems, fmt.Sprintf("unknown key '%s' in %s", k, f.Path))
+		}
+	}
+
+	return problems
+}
+
+// ValidateAliases reports aliases which are not plain hostnames, shadow
+// the public hosts of other providers or are defined for several providers
+func ValidateAliases(aliases map[client.RepositoryProvider][]string) []string {
+	problems := []string{}
+	owners := map[string]client.RepositoryProvider{}
+
+	for _, p := range client.RepositoryProviders() {
+		for _, alias := range aliases[p] {
+			if alias == "" {
+				problems = append(problems, fmt.Sprintf("empty alias for %s", p))
+				continue
+			}
+
+			if strings.ContainsAny(alias, ":/@ ") {
+				problems = append(problems, fmt.Sprintf(
+					"alias '%s' for %s is not a hostname, e.g. use 'example.com' instead of 'https://example.com'",
+					alias, p,
+				))
+			}
+
+			if builtin, err := client.ParseRepositoryProvider(alias, nil); err == nil && builtin != p {
+				problems = append(problems, fmt.Sprintf(
+					"alias '%s' for %s is the host of %s and is never used", alias, p, builtin,
+				))
+			}
+
+			if owner, ok := owners[alias]; ok && owner != p {
+				problems = append(problems, fmt.Sprintf(
+					"alias '%s' is defined for both %s and %s, %s is used", alias, owner, p, owner,
+				))
+				continue
+			}
+			owners[alias] = p
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,28 @@
+package configutils
+
+import (
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateKeys(t *testing.T) {
+	f := &ConfigFile{Path: "config.toml", values: map[string]interface{}{
+		"github": map[string]interface{}{"token": "x", "tokn": "x"},
+	}}
+
+	assert.Equal(t, []string{"unknown key 'github.tokn' in config.toml"}, ValidateKeys(f))
+}
+
+func TestValidateAliases(t *testing.T) {
+	assert.Empty(t, ValidateAliases(map[client.RepositoryProvider][]string{
+		client.RepositoryProviderEnum.GITHUB: {"github.example.com"},
+	}))
+
+	problems := ValidateAliases(map[client.RepositoryProvider][]string{
+		client.RepositoryProviderEnum.GITHUB:           {"https://github.example.com", "gitlab.com", "git.example.com"},
+		client.RepositoryProviderEnum.BITBUCKET_SERVER: {"git.example.com"},
+	})
+	assert.Len(t, problems, 3)
+}
@@ -0,0 +1,252 @@
+package credentialutils
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"preq/internal/pkg/client"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+var ErrMissingCredential = errors.New("missing credential")
+
+// ErrCredentialNotStored is returned when no git credential helper kept the
+// stored credential, e.g. when credential.helper is not configured
+var ErrCredentialNotStored = errors.New("the credential was not stored by any git credential helper, configure credential.helper")
+
+// Source describes where a credential was found
+type Source string
+
+const (
+	Source_ENV            Source = "environment variable"
+	Source_COMMAND        Source = "password command"
+	Source_CONFIG         Source = "configuration file"
+	Source_GIT_CREDENTIAL Source = "git credential helper"
+)
+
+type Credential struct {
+	Username string
+	Secret   string
+	Source   Source
+}
+
+// SecretKey returns the configuration key of the provider's secret,
+// Bitbucket cloud uses app passwords and the rest use access tokens
+func SecretKey(provider client.RepositoryProvider) string {
+	if provider == client.RepositoryProviderEnum.BITBUCKET {
+		return "password"
+	}
+
+	return "token"
+}
+
+// EnvName returns the environment variable of the provider's key,
+// e.g. PREQ_BITBUCKET_PASSWORD
+func EnvName(provider client.RepositoryProvider, key string) string {
+	return strings.ToUpper(fmt.Sprintf("PREQ_%s_%s", provider, key))
+}
+
+var defaultHosts = map[client.RepositoryProvider]string{
+	client.RepositoryProviderEnum.BITBUCKET: "bitbucket.org",
+	client.RepositoryProviderEnum.GITHUB:    "github.com",
+	client.RepositoryProviderEnum.GITLAB:    "gitlab.com",
+}
+
+// Host returns the hostname the credential helpers store the provider's
+// credentials for
+func Host(provider client.RepositoryProvider, config *viper.Viper) string {
+	baseURL := config.GetString(fmt.Sprintf("%s.baseUrl", provider))
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+
+	return defaultHosts[provider]
+}
+
+// GetUsername returns the username from the environment or
+// the configuration
+func GetUsername(provider client.RepositoryProvider, config *viper.Viper) string {
+	if v := os.Getenv(EnvName(provider, "username")); v != "" {
+		return v
+	}
+
+	return config.GetString(fmt.Sprintf("%s.username", provider))
+}
+
+// Resolve looks up the provider's secret from the environment, the password
+// command, the configuration and finally the git credential helpers
+func Resolve(
+	provider client.RepositoryProvider,
+	config *viper.Viper,
+) (*Credential, error) {
+	username := GetUsername(provider, config)
+	key := SecretKey(provider)
+
+	if v := os.Getenv(EnvName(provider, key)); v != "" {
+		return &Credential{Username: username, Secret: v, Source: Source_ENV}, nil
+	}
+
+	command := config.GetString(fmt.Sprintf("%s.passwordCommand", provider))
+	if command != "" {
+		v, err := runPasswordCommand(command)
+		if err != nil {
+			return nil, fmt.Errorf("password command failed: %w", err)
+		}
+
+		return &Credential{Username: username, Secret: v, Source: Source_COMMAND}, nil
+	}
+
+	if v := config.GetString(fmt.Sprintf("%s.%s", provider, key)); v != "" {
+		return &Credential{Username: username, Secret: v, Source: Source_CONFIG}, nil
+	}
+
+	host := Host(provider, config)
+	if host != "" {
+		values, err := gitCredential("fill", map[string]string{
+			"protocol": "https",
+			"host":     host,
+			"username": username,
+		})
+		if err == nil && values["password"] != "" {
+			if username == "" {
+				username = values["username"]
+			}
+
+			return &Credential{
+				Username: username,
+				Secret:   values["password"],
+				Source:   Source_GIT_CREDENTIAL,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w, set %s.%s or %s", ErrMissingCredential, provider, key, EnvName(provider, key))
+}
+
+// PrecedingSource returns the source of the credential which takes precedence
+// over the git credential helpers, or an empty source when there is none. The
+// password command is not run.
+func PrecedingSource(provider client.RepositoryProvider, config *viper.Viper) Source {
+	key := SecretKey(provider)
+	switch {
+	case os.Getenv(EnvName(provider, key)) != "":
+		return Source_ENV
+	case config.GetString(fmt.Sprintf("%s.passwordCommand", provider)) != "":
+		return Source_COMMAND
+	case config.GetString(fmt.Sprintf("%s.%s", provider, key)) != "":
+		return Source_CONFIG
+	}
+
+	return ""
+}
+
+// IsStored reports whether the git credential helpers have a credential for
+// the provider's host
+func IsStored(provider client.RepositoryProvider, config *viper.Viper) bool {
+	host := Host(provider, config)
+	if host == "" {
+		return false
+	}
+
+	values, err := gitCredential("fill", map[string]string{
+		"protocol": "https",
+		"host":     host,
+		"username": GetUsername(provider, config),
+	})
+
+	return err == nil && values["password"] != ""
+}
+
+func runPasswordCommand(command string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	// Only the first line is used, like pass(1) stores the password
+	line, _, _ := strings.Cut(string(output), "\n")
+	return strings.TrimSpace(line), nil
+}
+
+// Store saves the credential with the git credential helpers, e.g. the OS
+// keychain with osxkeychain, libsecret or Git Credential Manager. git
+// succeeds even when no helper is configured, so the credential is read back
+// to verify it was stored.
+func Store(provider client.RepositoryProvider, config *viper.Viper, c *Credential) error {
+	host := Host(provider, config)
+	_, err := gitCredential("approve", map[string]string{
+		"protocol": "https",
+		"host":     host,
+		"username": c.Username,
+		"password": c.Secret,
+	})
+	if err != nil {
+		return err
+	}
+
+	stored, err := gitCredential("fill", map[string]string{
+		"protocol": "https",
+		"host":     host,
+		"username": c.Username,
+	})
+	if err != nil || stored["password"] != c.Secret {
+		return ErrCredentialNotStored
+	}
+
+	return nil
+}
+
+// Erase removes the credential of the provider's host from the git credential
+// helpers. git uses the same credential for the HTTPS remotes of the host, so
+// it is removed for them too.
+func Erase(provider client.RepositoryProvider, config *viper.Viper) error {
+	_, err := gitCredential("reject", map[string]string{
+		"protocol": "https",
+		"host":     Host(provider, config),
+		"username": GetUsername(provider, config),
+	})
+	return err
+}
+
+// gitCredential runs a git credential action with the attributes as input,
+// the terminal prompt is disabled so a missing credential is not asked for
+func gitCredential(action string, attributes map[string]string) (map[string]string, error) {
+	input := &bytes.Buffer{}
+	for _, k := range []string{"protocol", "host", "username", "password"} {
+		if v := attributes[k]; v != "" {
+			fmt.Fprintf(input, "%s=%s\n", k, v)
+		}
+	}
+	input.WriteString("\n")
+
+	cmd := exec.Command("git", "credential", action)
+	cmd.Stdin = input
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCredentialOutput(output), nil
+}
+
+func parseCredentialOutput(output []byte) map[string]string {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		k, v, ok := strings.Cut(scanner.Text(), "=")
+		if ok {
+			values[k] = v
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,156 @@
+package credentialutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "PREQ_BITBUCKET_PASSWORD", EnvName(client.RepositoryProviderEnum.BITBUCKET, "password"))
+	assert.Equal(t, "PREQ_BITBUCKETSERVER_TOKEN", EnvName(client.RepositoryProviderEnum.BITBUCKET_SERVER, "token"))
+}
+
+func TestHost(t *testing.T) {
+	config := viper.New()
+	assert.Equal(t, "github.com", Host(client.RepositoryProviderEnum.GITHUB, config))
+	assert.Equal(t, "", Host(client.RepositoryProviderEnum.BITBUCKET_SERVER, config))
+
+	config.Set("gitlab.baseUrl", "https://gitlab.example.com/api/v4")
+	assert.Equal(t, "gitlab.example.com", Host(client.RepositoryProviderEnum.GITLAB, config))
+}
+
+func TestResolve(t *testing.T) {
+	provider := client.RepositoryProviderEnum.BITBUCKET
+
+	t.Run("prefers the environment variable", func(t *testing.T) {
+		t.Setenv("PREQ_BITBUCKET_PASSWORD", "env")
+		config := viper.New()
+		config.Set("bitbucket.username", "user")
+		config.Set("bitbucket.password", "config")
+
+		c, err := Resolve(provider, config)
+		assert.NoError(t, err)
+		assert.Equal(t, &Credential{Username: "user", Secret: "env", Source: Source_ENV}, c)
+	})
+
+	t.Run("runs the password command", func(t *testing.T) {
+		t.Setenv("PREQ_BITBUCKET_PASSWORD", "")
+		config := viper.New()
+		config.Set("bitbucket.passwordCommand", "printf 'secret\\nlogin: user\\n'")
+		config.Set("bitbucket.password", "config")
+
+		c, err := Resolve(provider, config)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", c.Secret)
+		assert.Equal(t, Source_COMMAND, c.Source)
+	})
+
+	t.Run("fails when the password command fails", func(t *testing.T) {
+		t.Setenv("PREQ_BITBUCKET_PASSWORD", "")
+		config := viper.New()
+		config.Set("bitbucket.passwordCommand", "exit 1")
+
+		_, err := Resolve(provider, config)
+		assert.Error(t, err)
+	})
+
+	t.Run("reads the configuration", func(t *testing.T) {
+		t.Setenv("PREQ_BITBUCKET_PASSWORD", "")
+		t.Setenv("PREQ_BITBUCKET_USERNAME", "env-user")
+		config := viper.New()
+		config.Set("bitbucket.username", "user")
+		config.Set("bitbucket.password", "config")
+
+		c, err := Resolve(provider, config)
+		assert.NoError(t, err)
+		assert.Equal(t, &Credential{Username: "env-user", Secret: "config", Source: Source_CONFIG}, c)
+	})
+}
+
+func Test_parseCredentialOutput(t *testing.T) {
+	values := parseCredentialOutput([]byte("protocol=https\nhost=github.com\nusername=user\npassword=a=b\n"))
+	assert.Equal(t, map[string]string{
+		"protocol": "https",
+		"host":     "github.com",
+		"username": "user",
+		"password": "a=b",
+	}, values)
+}
+
+// setTestGitConfig replaces the global git configuration for the test
+func setTestGitConfig(t *testing.T, config string) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gitconfig")
+	assert.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("GIT_CONFIG_GLOBAL", path)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+}
+
+func TestStore(t *testing.T) {
+	provider := client.RepositoryProviderEnum.GITHUB
+	credential := &Credential{Username: "user", Secret: "secret"}
+
+	t.Run("fails without a credential helper", func(t *testing.T) {
+		setTestGitConfig(t, "")
+
+		err := Store(provider, viper.New(), credential)
+		assert.ErrorIs(t, err, ErrCredentialNotStored)
+	})
+
+	t.Run("stores the credential", func(t *testing.T) {
+		store := filepath.ToSlash(filepath.Join(t.TempDir(), "credentials"))
+		setTestGitConfig(t, fmt.Sprintf("[credential]\n\thelper = store --file %s\n", store))
+
+		err := Store(provider, viper.New(), credential)
+		assert.NoError(t, err)
+
+		stored, err := gitCredential("fill", map[string]string{"protocol": "https", "host": "github.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", stored["password"])
+	})
+}
+
+func TestPrecedingSource(t *testing.T) {
+	provider := client.RepositoryProviderEnum.GITHUB
+
+	t.Run("is empty without other credentials", func(t *testing.T) {
+		t.Setenv("PREQ_GITHUB_TOKEN", "")
+		assert.Equal(t, Source(""), PrecedingSource(provider, viper.New()))
+	})
+
+	t.Run("finds the configured token", func(t *testing.T) {
+		t.Setenv("PREQ_GITHUB_TOKEN", "")
+		config := viper.New()
+		config.Set("github.token", "config")
+		assert.Equal(t, Source_CONFIG, PrecedingSource(provider, config))
+	})
+
+	t.Run("prefers the environment variable", func(t *testing.T) {
+		t.Setenv("PREQ_GITHUB_TOKEN", "env")
+		config := viper.New()
+		config.Set("github.token", "config")
+		assert.Equal(t, Source_ENV, PrecedingSource(provider, config))
+	})
+}
+
+func TestIsStored(t *testing.T) {
+	provider := client.RepositoryProviderEnum.GITHUB
+	store := filepath.ToSlash(filepath.Join(t.TempDir(), "credentials"))
+	setTestGitConfig(t, fmt.Sprintf("[credential]\n\thelper = store --file %s\n", store))
+
+	config := viper.New()
+	config.Set("github.username", "user")
+	assert.False(t, IsStored(provider, config))
+
+	err := Store(provider, config, &Credential{Username: "user", Secret: "secret"})
+	assert.NoError(t, err)
+	assert.True(t, IsStored(provider, config))
+}
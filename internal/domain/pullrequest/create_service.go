@@ -11,12 +11,16 @@ type CreateService struct {
 }
 
 type CreateOptions struct {
-	Repository  *client.Repository
-	Title       string
-	Source      string
-	Destination string
-	CloseBranch bool
-	Draft       bool
+	Repository           *client.Repository
+	Title                string
+	Description          string
+	Source               string
+	Destination          string
+	CloseBranch          bool
+	Draft                bool
+	Reviewers            []string
+	SkipDefaultReviewers bool
+	Author               string
 }
 
 func (cs *CreateService) Create(o *CreateOptions) (*Entity, error) {
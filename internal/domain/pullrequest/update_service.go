@@ -1,5 +1,7 @@
 package pullrequest
 
+import "preq/internal/pkg/client"
+
 type Updater interface {
 	Update(o *UpdateOptions) (*Entity, error)
 }
@@ -9,15 +11,19 @@ type UpdateService struct {
 }
 
 type UpdateOptions struct {
-	Title       string
-	Source      string
-	Destination string
-	CloseBranch bool
-	Draft       bool
+	Repository *client.Repository
+	ID         string
+	// Fields left nil are not changed
+	Title           *string
+	Description     *string
+	Destination     *string
+	Draft           *bool
+	AddReviewers    []string
+	RemoveReviewers []string
 }
 
 func (us *UpdateService) Update(o *UpdateOptions) (*Entity, error) {
-	return &Entity{}, nil
+	return us.updater.Update(o)
 }
 
 func NewUpdateService(c Updater) *UpdateService {
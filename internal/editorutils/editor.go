@@ -0,0 +1,59 @@
+package editorutils
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// GetEditor returns the command of the user's preferred editor, the
+// variables of only whitespace are skipped
+func GetEditor() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if e := strings.TrimSpace(os.Getenv(env)); e != "" {
+			return e
+		}
+	}
+
+	return "vi"
+}
+
+// Edit opens the content in the user's editor and returns the saved content
+// once the editor exits
+func Edit(content string, pattern string) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(content)
+	if err != nil {
+		f.Close()
+		return "", err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return "", err
+	}
+
+	// The editor may have arguments, e.g. "code --wait"
+	args := strings.Fields(GetEditor())
+	cmd := exec.Command(args[0], append(args[1:], f.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return "", err
+	}
+
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
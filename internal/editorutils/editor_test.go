@@ -0,0 +1,21 @@
+package editorutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEditor(t *testing.T) {
+	t.Run("prefers VISUAL", func(t *testing.T) {
+		t.Setenv("VISUAL", "code --wait")
+		t.Setenv("EDITOR", "nano")
+		assert.Equal(t, "code --wait", GetEditor())
+	})
+
+	t.Run("skips the whitespace only values", func(t *testing.T) {
+		t.Setenv("VISUAL", " ")
+		t.Setenv("EDITOR", "\t")
+		assert.Equal(t, "vi", GetEditor())
+	})
+}
@@ -0,0 +1,272 @@
+package gitutils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"preq/internal/pkg/client"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrDirtyWorktree = errors.New(
+	"the worktree has uncommitted changes, commit them or use --stash",
+)
+
+var ErrDivergedBranch = errors.New(
+	"the local branch has commits which are not in the pull request",
+)
+
+type CheckoutOptions struct {
+	// Branch is the local branch, named after the pull request when empty
+	Branch string
+	// Stash stashes the uncommitted changes instead of refusing to switch
+	Stash bool
+}
+
+type CheckoutResult struct {
+	Branch string
+	// Stashed is set when the uncommitted changes were stashed
+	Stashed bool
+}
+
+// runGit runs the git command in the directory and returns its trimmed
+// output, the output is part of the error when it fails
+func runGit(dir string, args ...string) (string, error) {
+	return runGitContext(context.Background(), dir, nil, args...)
+}
+
+// runGitContext is runGit with additional environment variables, the command
+// is killed once the context is done
+func runGitContext(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		return "", errors.Wrap(err, strings.TrimSpace(string(output)))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// PullRequestBranchName is the local branch of a checked out pull request
+func PullRequestBranchName(pr *client.PullRequest) string {
+	return fmt.Sprintf("pr/%s", pr.ID)
+}
+
+// pullRequestRef returns the ref of the pull request's source on the
+// destination repository, the providers except Bitbucket Cloud keep one for
+// every pull request, including the ones from forks
+func pullRequestRef(provider client.RepositoryProvider, pr *client.PullRequest) string {
+	switch provider {
+	case client.RepositoryProviderEnum.GITHUB:
+		return fmt.Sprintf("refs/pull/%s/head", pr.ID)
+	case client.RepositoryProviderEnum.GITLAB:
+		return fmt.Sprintf("refs/merge-requests/%s/head", pr.ID)
+	case client.RepositoryProviderEnum.BITBUCKET_SERVER:
+		return fmt.Sprintf("refs/pull-requests/%s/from", pr.ID)
+	}
+
+	return fmt.Sprintf("refs/heads/%s", pr.Source.Name)
+}
+
+// forkURL replaces the repository of the remote URL with the fork
+func forkURL(remoteURL string, fork string) (string, error) {
+	m := remoteURIRegexp.FindStringSubmatchIndex(remoteURL)
+	if m == nil {
+		return "", ErrUnableToParseRemoteRepositoryURI
+	}
+
+	return remoteURL[:m[4]] + fork + remoteURL[m[5]:], nil
+}
+
+// findRemote returns the name and the URL of the remote of the repository,
+// the origin when none of the remotes matches
+func findRemote(dir string, repo *client.Repository) (string, string, error) {
+	output, err := runGit(dir, "remote")
+	if err != nil {
+		return "", "", err
+	}
+
+	remotes := strings.Fields(output)
+	if len(remotes) == 0 {
+		return "", "", ErrNoRemotes
+	}
+
+	fallback := remotes[0]
+	for _, name := range remotes {
+		url, err := runGit(dir, "remote", "get-url", name)
+		if err != nil {
+			return "", "", err
+		}
+
+		m, err := extractRepositoryTokens(url)
+		if err == nil && strings.EqualFold(parseRepositoryName(repo.Provider, m[1]), repo.Name) {
+			return name, url, nil
+		}
+
+		if name == "origin" {
+			fallback = name
+		}
+	}
+
+	url, err := runGit(dir, "remote", "get-url", fallback)
+	if err != nil {
+		return "", "", err
+	}
+
+	return fallback, url, nil
+}
+
+// fetchSource returns the remote and the ref the pull request's source is
+// fetched from
+func fetchSource(
+	dir string,
+	repo *client.Repository,
+	pr *client.PullRequest,
+) (string, string, error) {
+	remote, url, err := findRemote(dir, repo)
+	if err != nil {
+		return "", "", err
+	}
+
+	ref := pullRequestRef(repo.Provider, pr)
+	fork := pr.Source.Repository
+	if repo.Provider == client.RepositoryProviderEnum.BITBUCKET &&
+		fork != "" && !strings.EqualFold(fork, repo.Name) {
+		remote, err = forkURL(url, fork)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return remote, ref, nil
+}
+
+func isWorktreeDirty(dir string) (bool, error) {
+	output, err := runGit(dir, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return false, err
+	}
+
+	return output != "", nil
+}
+
+// CheckoutPullRequest fetches the source of the pull request into the
+// repository at dir and switches to a local branch tracking it. An existing
+// branch is reset to the pull request unless it has local commits.
+func CheckoutPullRequest(
+	dir string,
+	repo *client.Repository,
+	pr *client.PullRequest,
+	o *CheckoutOptions,
+) (*CheckoutResult, error) {
+	result := &CheckoutResult{Branch: o.Branch}
+	if result.Branch == "" {
+		result.Branch = PullRequestBranchName(pr)
+	}
+
+	dirty, err := isWorktreeDirty(dir)
+	if err != nil {
+		return nil, err
+	}
+	if dirty && !o.Stash {
+		return nil, ErrDirtyWorktree
+	}
+
+	remote, ref, err := fetchSource(dir, repo, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = runGit(dir, "fetch", remote, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := runGit(dir, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	// An existing branch is reset to the pull request, e.g. after a force
+	// push, unless it has commits of its own
+	local, err := hasLocalCommits(dir, result.Branch, hash)
+	if err != nil {
+		return nil, err
+	}
+	if local {
+		return nil, ErrDivergedBranch
+	}
+
+	if dirty {
+		_, err = runGit(dir, "stash", "push", "-m", fmt.Sprintf("preq checkout %s", result.Branch))
+		if err != nil {
+			return nil, err
+		}
+		result.Stashed = true
+	}
+
+	_, err = runGit(dir, "switch", "-C", result.Branch, hash)
+	if err != nil {
+		return result, err
+	}
+
+	_, err = runGit(dir, "config", fmt.Sprintf("branch.%s.remote", result.Branch), remote)
+	if err != nil {
+		return result, err
+	}
+
+	_, err = runGit(dir, "config", fmt.Sprintf("branch.%s.merge", result.Branch), ref)
+	if err != nil {
+		return result, err
+	}
+
+	_, err = runGit(dir, "config", headConfigKey(result.Branch), hash)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// headConfigKey is the configuration key of the pull request's commit the
+// branch was last checked out at
+func headConfigKey(branch string) string {
+	return fmt.Sprintf("branch.%s.preqHead", branch)
+}
+
+// hasLocalCommits reports whether the branch has commits which are neither
+// in the pull request's commit nor in the one it was last checked out at, the
+// commits dropped by a force push are not local
+func hasLocalCommits(dir string, branch string, hash string) (bool, error) {
+	_, err := runGit(dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	if err != nil {
+		return false, nil
+	}
+
+	args := []string{"rev-list", "--count", "refs/heads/" + branch, "^" + hash}
+	previous, err := runGit(dir, "config", headConfigKey(branch))
+	if err == nil && previous != "" {
+		_, err = runGit(dir, "cat-file", "-e", previous+"^{commit}")
+		if err == nil {
+			args = append(args, "^"+previous)
+		}
+	}
+
+	count, err := runGit(dir, args...)
+	if err != nil {
+		return false, err
+	}
+
+	return count != "0", nil
+}
@@ -0,0 +1,144 @@
+package gitutils
+
+import (
+	"os"
+	"path/filepath"
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runTestGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	args = append([]string{"-c", "user.name=preq", "-c", "user.email=preq@example.com"}, args...)
+	output, err := runGit(dir, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return output
+}
+
+// newUpstream creates a repository with a feature branch, which is also
+// published as a GitHub pull request ref, and a clone of it
+func newUpstream(t *testing.T) (string, string) {
+	root := t.TempDir()
+	upstream := filepath.Join(root, "upstream")
+	clone := filepath.Join(root, "clone")
+
+	runTestGit(t, root, "init", "-q", "-b", "main", upstream)
+	runTestGit(t, upstream, "commit", "-q", "--allow-empty", "-m", "initial")
+	runTestGit(t, upstream, "switch", "-q", "-c", "feature")
+	runTestGit(t, upstream, "commit", "-q", "--allow-empty", "-m", "feature")
+	runTestGit(t, upstream, "update-ref", "refs/pull/7/head", "feature")
+	runTestGit(t, upstream, "switch", "-q", "main")
+	runTestGit(t, root, "clone", "-q", upstream, clone)
+
+	return upstream, clone
+}
+
+func TestCheckoutPullRequest(t *testing.T) {
+	pr := &client.PullRequest{ID: "7", Source: client.PullRequestBranch{Name: "feature"}}
+
+	t.Run("creates a tracking branch", func(t *testing.T) {
+		upstream, clone := newUpstream(t)
+		repo := &client.Repository{Provider: client.RepositoryProviderEnum.BITBUCKET, Name: "owner/repo"}
+
+		result, err := CheckoutPullRequest(clone, repo, pr, &CheckoutOptions{})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, "pr/7", result.Branch)
+		assert.False(t, result.Stashed)
+		assert.Equal(t, "pr/7", runTestGit(t, clone, "branch", "--show-current"))
+		assert.Equal(t, runTestGit(t, upstream, "rev-parse", "feature"), runTestGit(t, clone, "rev-parse", "HEAD"))
+		assert.Equal(t, "refs/heads/feature", runTestGit(t, clone, "config", "branch.pr/7.merge"))
+	})
+
+	t.Run("fetches the pull request ref and updates the branch", func(t *testing.T) {
+		upstream, clone := newUpstream(t)
+		repo := &client.Repository{Provider: client.RepositoryProviderEnum.GITHUB, Name: "owner/repo"}
+
+		_, err := CheckoutPullRequest(clone, repo, pr, &CheckoutOptions{})
+		assert.NoError(t, err)
+
+		runTestGit(t, upstream, "switch", "-q", "feature")
+		runTestGit(t, upstream, "commit", "-q", "--allow-empty", "-m", "update")
+		runTestGit(t, upstream, "update-ref", "refs/pull/7/head", "feature")
+		runTestGit(t, clone, "switch", "-q", "main")
+
+		_, err = CheckoutPullRequest(clone, repo, pr, &CheckoutOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, runTestGit(t, upstream, "rev-parse", "feature"), runTestGit(t, clone, "rev-parse", "HEAD"))
+		assert.Equal(t, "refs/pull/7/head", runTestGit(t, clone, "config", "branch.pr/7.merge"))
+	})
+
+	t.Run("resets the branch after a force push", func(t *testing.T) {
+		upstream, clone := newUpstream(t)
+		repo := &client.Repository{Provider: client.RepositoryProviderEnum.GITHUB, Name: "owner/repo"}
+
+		_, err := CheckoutPullRequest(clone, repo, pr, &CheckoutOptions{})
+		assert.NoError(t, err)
+
+		runTestGit(t, upstream, "switch", "-q", "feature")
+		runTestGit(t, upstream, "commit", "-q", "--amend", "--allow-empty", "-m", "amended")
+		runTestGit(t, upstream, "update-ref", "refs/pull/7/head", "feature")
+
+		_, err = CheckoutPullRequest(clone, repo, pr, &CheckoutOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, runTestGit(t, upstream, "rev-parse", "feature"), runTestGit(t, clone, "rev-parse", "HEAD"))
+	})
+
+	t.Run("refuses a branch with local commits", func(t *testing.T) {
+		_, clone := newUpstream(t)
+		repo := &client.Repository{Provider: client.RepositoryProviderEnum.GITHUB, Name: "owner/repo"}
+
+		_, err := CheckoutPullRequest(clone, repo, pr, &CheckoutOptions{})
+		assert.NoError(t, err)
+
+		runTestGit(t, clone, "commit", "-q", "--allow-empty", "-m", "local")
+		local := runTestGit(t, clone, "rev-parse", "HEAD")
+
+		_, err = CheckoutPullRequest(clone, repo, pr, &CheckoutOptions{})
+		assert.ErrorIs(t, err, ErrDivergedBranch)
+		assert.Equal(t, local, runTestGit(t, clone, "rev-parse", "pr/7"))
+	})
+
+	t.Run("refuses a dirty worktree unless stashing", func(t *testing.T) {
+		_, clone := newUpstream(t)
+		repo := &client.Repository{Provider: client.RepositoryProviderEnum.BITBUCKET, Name: "owner/repo"}
+
+		file := filepath.Join(clone, "file.txt")
+		assert.NoError(t, os.WriteFile(file, []byte("a"), 0o644))
+		runTestGit(t, clone, "add", "file.txt")
+
+		_, err := CheckoutPullRequest(clone, repo, pr, &CheckoutOptions{})
+		assert.ErrorIs(t, err, ErrDirtyWorktree)
+		assert.Equal(t, "main", runTestGit(t, clone, "branch", "--show-current"))
+
+		result, err := CheckoutPullRequest(clone, repo, pr, &CheckoutOptions{Stash: true, Branch: "review"})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, result.Stashed)
+		assert.Equal(t, "review", runTestGit(t, clone, "branch", "--show-current"))
+		assert.Contains(t, runTestGit(t, clone, "stash", "list"), "preq checkout review")
+	})
+}
+
+func Test_forkURL(t *testing.T) {
+	for _, tt := range []struct{ url, want string }{
+		{"git@bitbucket.org:owner/repo.git", "git@bitbucket.org:fork/repo.git"},
+		{"https://user@bitbucket.org/owner/repo.git", "https://user@bitbucket.org/fork/repo.git"},
+		{"ssh://git@bitbucket.org/owner/repo", "ssh://git@bitbucket.org/fork/repo"},
+	} {
+		got, err := forkURL(tt.url, "fork/repo")
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := forkURL("not a url", "fork/repo")
+	assert.ErrorIs(t, err, ErrUnableToParseRemoteRepositoryURI)
+}
@@ -0,0 +1,311 @@
+package gitutils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/pkg/errors"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// DefaultContextLines is the number of unchanged lines around the changes,
+// the same as git's
+const DefaultContextLines = 3
+
+type DiffOptions struct {
+	// IgnoreWhitespace hides the lines which only differ in whitespace, like
+	// git diff --ignore-all-space
+	IgnoreWhitespace bool
+	// ContextLines is the number of unchanged lines around the changes
+	ContextLines int
+}
+
+func DefaultDiffOptions() *DiffOptions {
+	return &DiffOptions{ContextLines: DefaultContextLines}
+}
+
+// renameScore is the similarity of a renamed file, the same as git's
+const renameScore = 50
+
+// GetDiffPatch returns the changes of toHash since its merge base with
+// fromHash, like git diff fromHash...toHash. The diff is computed in-process,
+// git is only run when go-git fails to compute it and for diffs without
+// context lines, which go-git numbers differently.
+func (git *GoGit) GetDiffPatch(fromHash string, toHash string, o *DiffOptions) ([]byte, error) {
+	if o == nil {
+		o = DefaultDiffOptions()
+	}
+	if o.ContextLines < 1 {
+		return git.diffPatchCLI(fromHash, toHash, o)
+	}
+
+	patch, err := git.diffPatch(fromHash, toHash, o)
+	if err == nil || errors.Is(err, ErrCommitHashNotFound) {
+		return patch, err
+	}
+
+	patch, cliErr := git.diffPatchCLI(fromHash, toHash, o)
+	if errors.Is(cliErr, exec.ErrNotFound) {
+		return nil, err
+	}
+
+	return patch, cliErr
+}
+
+func (git *GoGit) resolveCommit(hash string) (*object.Commit, error) {
+	h, err := git.goGit.ResolveRevision(plumbing.Revision(hash))
+	if errors.Is(err, plumbing.ErrReferenceNotFound) || errors.Is(err, plumbing.ErrObjectNotFound) {
+		return nil, ErrCommitHashNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := git.goGit.CommitObject(*h)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return nil, ErrCommitHashNotFound
+	}
+
+	return c, err
+}
+
+func (git *GoGit) diffPatch(fromHash string, toHash string, o *DiffOptions) ([]byte, error) {
+	from, err := git.resolveCommit(fromHash)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := git.resolveCommit(toHash)
+	if err != nil {
+		return nil, err
+	}
+
+	bases, err := from.MergeBase(to)
+	if err != nil {
+		return nil, err
+	}
+	if len(bases) == 0 {
+		return nil, ErrAncestorCommitNotFound
+	}
+
+	baseTree, err := bases[0].Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := *object.DefaultDiffTreeOptions
+	opts.RenameScore = renameScore
+	changes, err := object.DiffTreeWithOptions(context.Background(), baseTree, toTree, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var patch fdiff.Patch
+	patch, err = changes.Patch()
+	if err != nil {
+		return nil, err
+	}
+
+	if o.IgnoreWhitespace {
+		patch = ignoreWhitespace(patch)
+	}
+
+	return encodePatch(patch, o.ContextLines)
+}
+
+// encodePatch encodes the patch in the unified format of git diff. go-git
+// leaves out the similarity of the renamed files, which the diff parsers
+// rely on for the renames without any changes.
+func encodePatch(p fdiff.Patch, contextLines int) ([]byte, error) {
+	b := &bytes.Buffer{}
+	for _, fp := range p.FilePatches() {
+		fb := &bytes.Buffer{}
+		err := fdiff.NewUnifiedEncoder(fb, contextLines).Encode(&patch{
+			filePatches: []fdiff.FilePatch{fp},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		from, to := fp.Files()
+		if from != nil && to != nil && from.Path() != to.Path() && from.Hash() == to.Hash() {
+			header, rest, _ := bytes.Cut(fb.Bytes(), []byte("\n"))
+			b.Write(header)
+			b.WriteString("\nsimilarity index 100%\n")
+			b.Write(rest)
+			continue
+		}
+
+		b.Write(fb.Bytes())
+	}
+
+	return b.Bytes(), nil
+}
+
+func (git *GoGit) diffPatchCLI(fromHash string, toHash string, o *DiffOptions) ([]byte, error) {
+	args := []string{
+		"diff",
+		"--no-color",
+		"--no-ext-diff",
+		fmt.Sprintf("--find-renames=%d%%", renameScore),
+		fmt.Sprintf("--unified=%d", o.ContextLines),
+	}
+	if o.IgnoreWhitespace {
+		args = append(args, "--ignore-all-space")
+	}
+	args = append(args, fmt.Sprintf("%s...%s", fromHash, toHash))
+
+	wt, err := git.goGit.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = wt.Filesystem.Root()
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		value := string(output)
+		if strings.Contains(value, "unknown revision") ||
+			strings.Contains(value, "Invalid symmetric difference") {
+			return nil, ErrCommitHashNotFound
+		}
+		return nil, errors.Wrap(err, value)
+	}
+
+	return output, nil
+}
+
+type patch struct {
+	filePatches []fdiff.FilePatch
+	message     string
+}
+
+func (p *patch) FilePatches() []fdiff.FilePatch {
+	return p.filePatches
+}
+
+func (p *patch) Message() string {
+	return p.message
+}
+
+// filePatch replaces the chunks of a file's patch
+type filePatch struct {
+	fdiff.FilePatch
+	chunks []fdiff.Chunk
+}
+
+func (p *filePatch) Chunks() []fdiff.Chunk {
+	return p.chunks
+}
+
+type chunk struct {
+	content string
+	op      fdiff.Operation
+}
+
+func (c *chunk) Content() string {
+	return c.content
+}
+
+func (c *chunk) Type() fdiff.Operation {
+	return c.op
+}
+
+// ignoreWhitespace recomputes the changes of the modified files ignoring
+// whitespace, the files without any other changes are left out
+func ignoreWhitespace(p fdiff.Patch) fdiff.Patch {
+	result := &patch{message: p.Message()}
+	for _, fp := range p.FilePatches() {
+		from, to := fp.Files()
+		if fp.IsBinary() || from == nil || to == nil {
+			result.filePatches = append(result.filePatches, fp)
+			continue
+		}
+
+		chunks, changed := whitespaceChunks(fp.Chunks())
+		if !changed && from.Path() == to.Path() && from.Mode() == to.Mode() {
+			continue
+		}
+
+		result.filePatches = append(result.filePatches, &filePatch{
+			FilePatch: fp,
+			chunks:    chunks,
+		})
+	}
+
+	return result
+}
+
+// splitLines splits the content into lines keeping the line endings
+func splitLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// normalizeLines removes the whitespace of the lines, every line of the
+// result ends with a line ending so the lines can be counted
+func normalizeLines(lines []string) string {
+	b := strings.Builder{}
+	for _, line := range lines {
+		b.WriteString(strings.Join(strings.Fields(line), ""))
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// whitespaceChunks diffs the lines of the chunks ignoring whitespace, the
+// unchanged lines are taken from the new version of the file
+func whitespaceChunks(chunks []fdiff.Chunk) ([]fdiff.Chunk, bool) {
+	var before, after strings.Builder
+	for _, c := range chunks {
+		if c.Type() != fdiff.Add {
+			before.WriteString(c.Content())
+		}
+		if c.Type() != fdiff.Delete {
+			after.WriteString(c.Content())
+		}
+	}
+
+	beforeLines := splitLines(before.String())
+	afterLines := splitLines(after.String())
+
+	result := []fdiff.Chunk{}
+	changed := false
+	i, j := 0, 0
+	for _, d := range diff.Do(normalizeLines(beforeLines), normalizeLines(afterLines)) {
+		n := strings.Count(d.Text, "\n")
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			result = append(result, &chunk{strings.Join(afterLines[j:j+n], ""), fdiff.Equal})
+			i, j = i+n, j+n
+		case diffmatchpatch.DiffDelete:
+			result = append(result, &chunk{strings.Join(beforeLines[i:i+n], ""), fdiff.Delete})
+			i += n
+			changed = true
+		case diffmatchpatch.DiffInsert:
+			result = append(result, &chunk{strings.Join(afterLines[j:j+n], ""), fdiff.Add})
+			j += n
+			changed = true
+		}
+	}
+
+	return result, changed
+}
@@ -0,0 +1,118 @@
+package gitutils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// newDiffRepo creates a repository with a main branch and a feature branch
+// which renames, modifies and adds files
+func newDiffRepo(t *testing.T) (*GoGit, string, string) {
+	dir := t.TempDir()
+	write := func(name string, content string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	runTestGit(t, dir, "init", "-q", "-b", "main")
+	write("moved.txt", "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n")
+	write("code.go", "func a() {\n\treturn 1\n}\n\nfunc b() {\n\treturn 2\n}\n")
+	runTestGit(t, dir, "add", ".")
+	runTestGit(t, dir, "commit", "-q", "-m", "initial")
+
+	runTestGit(t, dir, "switch", "-q", "-c", "feature")
+	runTestGit(t, dir, "mv", "moved.txt", "renamed.txt")
+	write("code.go", "func a() {\n    return 1\n}\n\nfunc b() {\n\treturn 3\n}\n")
+	write("image.bin", "\x00\x01\x02")
+	runTestGit(t, dir, "add", ".")
+	runTestGit(t, dir, "commit", "-q", "-m", "feature")
+
+	// The destination moves on, its changes are not part of the diff
+	runTestGit(t, dir, "switch", "-q", "main")
+	write("later.txt", "later\n")
+	runTestGit(t, dir, "add", ".")
+	runTestGit(t, dir, "commit", "-q", "-m", "later")
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &GoGit{goGit: repo}, runTestGit(t, dir, "rev-parse", "main"), runTestGit(t, dir, "rev-parse", "feature")
+}
+
+func TestGetDiffPatch(t *testing.T) {
+	g, main, feature := newDiffRepo(t)
+
+	t.Run("diffs since the merge base", func(t *testing.T) {
+		patch, err := g.GetDiffPatch(main, feature, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		p := string(patch)
+		assert.NotContains(t, p, "later.txt")
+		assert.Contains(t, p, "similarity index 100%\nrename from moved.txt\nrename to renamed.txt\n")
+		assert.Contains(t, p, "Binary files /dev/null and b/image.bin differ\n")
+		assert.Contains(t, p, "-\treturn 1\n+    return 1\n")
+		assert.Contains(t, p, "-\treturn 2\n+\treturn 3\n")
+	})
+
+	t.Run("ignores whitespace", func(t *testing.T) {
+		patch, err := g.GetDiffPatch(main, feature, &DiffOptions{IgnoreWhitespace: true, ContextLines: 1})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		p := string(patch)
+		assert.NotContains(t, p, "-\treturn 1\n")
+		assert.Contains(t, p, "@@ -5,3 +5,3 @@\n func b() {\n-\treturn 2\n+\treturn 3\n }\n")
+	})
+
+	t.Run("matches git diff", func(t *testing.T) {
+		for _, o := range []*DiffOptions{
+			DefaultDiffOptions(),
+			{ContextLines: 1, IgnoreWhitespace: true},
+		} {
+			patch, err := g.diffPatch(main, feature, o)
+			assert.NoError(t, err)
+			cliPatch, err := g.diffPatchCLI(main, feature, o)
+			assert.NoError(t, err)
+
+			assert.Equal(t, hunks(string(cliPatch)), hunks(string(patch)))
+		}
+	})
+
+	t.Run("reports unknown commits", func(t *testing.T) {
+		_, err := g.GetDiffPatch(main, "0123456789abcdef0123456789abcdef01234567", nil)
+		assert.ErrorIs(t, err, ErrCommitHashNotFound)
+
+		_, err = g.diffPatchCLI(main, "0123456789abcdef0123456789abcdef01234567", DefaultDiffOptions())
+		assert.ErrorIs(t, err, ErrCommitHashNotFound)
+	})
+}
+
+// hunks returns the hunk lines of the patch without the sections of the hunk
+// headers, git and go-git differ in the headers of the files and the sections
+func hunks(patch string) []string {
+	lines := []string{}
+	inHunk := false
+	for _, line := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(line, "diff --git") {
+			inHunk = false
+		}
+		if strings.HasPrefix(line, "@@") {
+			inHunk = true
+			line = line[:strings.LastIndex(line, "@@")+2]
+		}
+		if inHunk {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
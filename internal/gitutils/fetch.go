@@ -0,0 +1,108 @@
+package gitutils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"preq/internal/pkg/client"
+)
+
+// nonInteractiveEnv makes git fail instead of prompting for credentials,
+// there is no terminal to prompt in while the TUI is running. The SSH
+// command is only replaced when the user has not configured one.
+func nonInteractiveEnv(dir string) []string {
+	env := []string{"GIT_TERMINAL_PROMPT=0"}
+	if os.Getenv("GIT_SSH_COMMAND") != "" || os.Getenv("GIT_SSH") != "" {
+		return env
+	}
+
+	if _, err := runGit(dir, "config", "core.sshCommand"); err == nil {
+		return env
+	}
+
+	return append(env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+}
+
+func hasCommit(dir string, hash string) bool {
+	if hash == "" {
+		return false
+	}
+
+	_, err := runGit(dir, "cat-file", "-e", hash+"^{commit}")
+	return err == nil
+}
+
+// fetch fetches the refspecs from the remote without prompting for
+// credentials
+func fetch(ctx context.Context, dir string, remote string, refspecs ...string) error {
+	args := append([]string{"fetch", "--quiet", "--no-tags", remote}, refspecs...)
+	_, err := runGitContext(ctx, dir, nonInteractiveEnv(dir), args...)
+	return err
+}
+
+// FetchPullRequest fetches the commits of the pull request missing in the
+// repository at dir, only the destination branch and the source ref of the
+// pull request are fetched. ErrCommitHashNotFound is returned when the
+// commits are still missing afterwards, e.g. the pull request was updated
+// in the meantime.
+func FetchPullRequest(
+	ctx context.Context,
+	dir string,
+	repo *client.Repository,
+	pr *client.PullRequest,
+) error {
+	if hasCommit(dir, pr.Source.Hash) && hasCommit(dir, pr.Destination.Hash) {
+		return nil
+	}
+
+	remote, _, err := findRemote(dir, repo)
+	if err != nil {
+		return err
+	}
+
+	sourceRemote, sourceRef, err := fetchSource(dir, repo, pr)
+	if err != nil {
+		return err
+	}
+
+	refspecs := map[string][]string{remote: {}}
+	if pr.Destination.Name != "" {
+		refspecs[remote] = append(refspecs[remote], fmt.Sprintf(
+			"+refs/heads/%[2]s:refs/remotes/%[1]s/%[2]s",
+			remote,
+			pr.Destination.Name,
+		))
+	}
+	refspecs[sourceRemote] = append(refspecs[sourceRemote], sourceRef)
+
+	// The destination remote first, the source is fetched from the fork's
+	// URL for Bitbucket Cloud
+	for _, r := range []string{remote, sourceRemote} {
+		if len(refspecs[r]) == 0 {
+			continue
+		}
+
+		err = fetch(ctx, dir, r, refspecs[r]...)
+		if err != nil {
+			return err
+		}
+		delete(refspecs, r)
+	}
+
+	if !hasCommit(dir, pr.Source.Hash) || !hasCommit(dir, pr.Destination.Hash) {
+		return ErrCommitHashNotFound
+	}
+
+	return nil
+}
+
+// FetchRepository fetches the remote of the repository at dir with its
+// configured refspecs
+func FetchRepository(ctx context.Context, dir string, repo *client.Repository) error {
+	remote, _, err := findRemote(dir, repo)
+	if err != nil {
+		return err
+	}
+
+	return fetch(ctx, dir, remote)
+}
@@ -0,0 +1,56 @@
+package gitutils
+
+import (
+	"context"
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchPullRequest(t *testing.T) {
+	repo := &client.Repository{Provider: client.RepositoryProviderEnum.GITHUB, Name: "owner/repo"}
+
+	t.Run("fetches the missing commits", func(t *testing.T) {
+		upstream, clone := newUpstream(t)
+		runTestGit(t, upstream, "commit", "-q", "--allow-empty", "-m", "main update")
+		runTestGit(t, upstream, "switch", "-q", "feature")
+		runTestGit(t, upstream, "commit", "-q", "--allow-empty", "-m", "feature update")
+		runTestGit(t, upstream, "update-ref", "refs/pull/7/head", "feature")
+
+		pr := &client.PullRequest{
+			ID:          "7",
+			Source:      client.PullRequestBranch{Name: "feature", Hash: runTestGit(t, upstream, "rev-parse", "feature")},
+			Destination: client.PullRequestBranch{Name: "main", Hash: runTestGit(t, upstream, "rev-parse", "main")},
+		}
+		assert.False(t, hasCommit(clone, pr.Source.Hash))
+
+		err := FetchPullRequest(context.Background(), clone, repo, pr)
+		assert.NoError(t, err)
+		assert.True(t, hasCommit(clone, pr.Source.Hash))
+		assert.Equal(t, pr.Destination.Hash, runTestGit(t, clone, "rev-parse", "origin/main"))
+	})
+
+	t.Run("reports commits missing on the remote", func(t *testing.T) {
+		_, clone := newUpstream(t)
+		pr := &client.PullRequest{
+			ID:          "7",
+			Source:      client.PullRequestBranch{Name: "feature", Hash: "0123456789abcdef0123456789abcdef01234567"},
+			Destination: client.PullRequestBranch{Name: "main"},
+		}
+
+		err := FetchPullRequest(context.Background(), clone, repo, pr)
+		assert.ErrorIs(t, err, ErrCommitHashNotFound)
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		_, clone := newUpstream(t)
+		pr := &client.PullRequest{ID: "7", Source: client.PullRequestBranch{Name: "feature"}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := FetchPullRequest(ctx, clone, repo, pr)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -1,8 +1,8 @@
 package gitutils
 
 import (
+	"container/heap"
 	"fmt"
-	"os/exec"
 	"path/filepath"
 	"preq/internal/pkg/client"
 	"preq/internal/pkg/fs"
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/pkg/errors"
 )
@@ -28,6 +29,9 @@ var (
 	ErrCannotFindAnyBranchReference = errors.New(
 		"cannot find any branch reference",
 	)
+	ErrNoRemotes = errors.New(
+		"the repository has no remotes",
+	)
 )
 
 func IsDirGitRepo(path string) bool {
@@ -86,9 +90,15 @@ var getRemoteInfoList = func(git *GoGit, aliases map[client.RepositoryProvider][
 	return repos, nil
 }
 
+// Matches SCP-like SSH remotes (git@host:owner/repo.git) as well as
+// ssh:// and http(s):// remotes, optionally with a port and without
+// the .git suffix. Nested paths are kept (GitLab subgroups).
+var remoteURIRegexp = regexp.MustCompile(
+	`^(?:[a-z][a-z0-9+.-]*://)?(?:[^@/]+@)?([^:/]+)(?::\d+)?[:/](.+?)(?:\.git)?/?$`,
+)
+
 var extractRepositoryTokens = func(uri string) ([]string, error) {
-	r := regexp.MustCompile(`@([^:/]+)[:/](.*)\.git$`)
-	m := r.FindStringSubmatch(uri)
+	m := remoteURIRegexp.FindStringSubmatch(uri)
 	if len(m) != 3 {
 		return nil, ErrUnableToParseRemoteRepositoryURI
 	}
@@ -113,10 +123,26 @@ var parseRepositoryString = func(repoString string, aliases map[client.Repositor
 
 	return &client.Repository{
 		Provider: p,
-		Name:     m[1],
+		Name:     parseRepositoryName(p, m[1]),
 	}, nil
 }
 
+// parseRepositoryName strips provider specific prefixes from the path of
+// a remote URI. HTTP remotes of Bitbucket Server are in the
+// `/scm/PROJECT/repository` form, optionally below a context path.
+func parseRepositoryName(p client.RepositoryProvider, path string) string {
+	if p != client.RepositoryProviderEnum.BITBUCKET_SERVER {
+		return path
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) >= 3 && parts[len(parts)-3] == "scm" {
+		return strings.Join(parts[len(parts)-2:], "/")
+	}
+
+	return path
+}
+
 type branchCommitMap map[string]*object.Commit
 
 var getBranchCommits = func(r gitRepository, branches []string) (branchCommitMap, error) {
@@ -170,7 +196,8 @@ type GitUtilsClient interface {
 	GetCurrentCommitMessage() (string, error)
 	GetCurrentBranch() (string, error)
 	GetBranchLastCommitMessage(name string) (string, error)
-	GetDiffPatch(fromHash string, toHash string) ([]byte, error)
+	GetDiffPatch(fromHash string, toHash string, o *DiffOptions) ([]byte, error)
+	GetCommitMessages(source string, destination string) ([]string, error)
 }
 
 type GoGit struct {
@@ -188,29 +215,6 @@ func new(path string) (GitUtilsClient, error) {
 	}, err
 }
 
-func (git *GoGit) GetDiffPatch(fromHash string, toHash string) ([]byte, error) {
-	cmd := exec.Command("git", "diff", fmt.Sprintf("%s...%s", fromHash, toHash))
-	cfg, err := git.goGit.Worktree()
-	if err != nil {
-		return nil, err
-	}
-
-	root := cfg.Filesystem.Root()
-	cmd.Dir = root
-
-	// Capture the output of the command
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		value := string(output)
-		if strings.Contains(value, "unknown revision") {
-			return nil, ErrCommitHashNotFound
-		}
-		return nil, errors.Wrap(err, value)
-	}
-
-	return output, nil
-}
-
 // TODO: Return a Branch type? Or rename to GetClosesBranchName?
 func (git *GoGit) GetClosestBranch(branches []string) (string, error) {
 	// TODO: What if the history branches? Use BFS for looking up history. Perhaps git.GetLog()?
@@ -236,6 +240,10 @@ func (git *GoGit) GetRemoteInfo(
 		return nil, err
 	}
 
+	if len(repos) == 0 {
+		return nil, ErrNoRemotes
+	}
+
 	return repos[0], nil
 }
 
@@ -257,6 +265,157 @@ func (git *GoGit) GetCurrentCommitMessage() (string, error) {
 	return c.Message, nil
 }
 
+// branchCommit resolves a local branch, falling back to the branch of the
+// same name on any of the remotes
+func (git *GoGit) branchCommit(name string) (*object.Commit, error) {
+	c, err := git.Git.BranchCommit(name)
+	if err == nil {
+		return c, nil
+	}
+
+	remotes, rErr := git.goGit.Remotes()
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	for _, r := range remotes {
+		ref, rErr := git.goGit.Reference(
+			plumbing.NewRemoteReferenceName(r.Config().Name, name),
+			true,
+		)
+		if rErr == nil {
+			return git.goGit.CommitObject(ref.Hash())
+		}
+	}
+
+	return nil, err
+}
+
+// GetCommitMessages returns the messages of the commits on the source branch
+// which are not on the destination branch, newest first
+func (git *GoGit) GetCommitMessages(
+	source string,
+	destination string,
+) ([]string, error) {
+	sc, err := git.branchCommit(source)
+	if err != nil {
+		return nil, err
+	}
+
+	dc, err := git.branchCommit(destination)
+	if err != nil {
+		return nil, err
+	}
+
+	return logMessages(git.goGit, sc.Hash, dc.Hash)
+}
+
+const (
+	// walkSource marks the commits reachable from the source commit
+	walkSource = 1 << iota
+	// walkExcluded marks the commits reachable from the excluded commit
+	walkExcluded
+)
+
+type walkItem struct {
+	commit *object.Commit
+	// order keeps the commits of the same time in the order they were queued
+	order int
+}
+
+// walkQueue orders the commits from the newest to the oldest
+type walkQueue []*walkItem
+
+func (q walkQueue) Len() int { return len(q) }
+
+func (q walkQueue) Less(i, j int) bool {
+	ti, tj := q[i].commit.Committer.When, q[j].commit.Committer.When
+	if !ti.Equal(tj) {
+		return ti.After(tj)
+	}
+
+	return q[i].order < q[j].order
+}
+
+func (q walkQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *walkQueue) Push(x interface{}) { *q = append(*q, x.(*walkItem)) }
+
+func (q *walkQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// logMessages returns the messages of the commits reachable from the from
+// commit but not from the excluded one, like git log exclude..from. The
+// history is walked from the newest commit until only the excluded commits
+// are left, so the commits of every merge parent are found.
+func logMessages(
+	repo *git.Repository,
+	from plumbing.Hash,
+	exclude plumbing.Hash,
+) ([]string, error) {
+	flags := map[plumbing.Hash]int{}
+	queue := &walkQueue{}
+	order := 0
+	push := func(c *object.Commit, flag int) {
+		if flags[c.Hash]|flag == flags[c.Hash] {
+			return
+		}
+
+		flags[c.Hash] |= flag
+		heap.Push(queue, &walkItem{commit: c, order: order})
+		order++
+	}
+
+	for _, start := range []struct {
+		hash plumbing.Hash
+		flag int
+	}{{from, walkSource}, {exclude, walkExcluded}} {
+		c, err := repo.CommitObject(start.hash)
+		if err != nil {
+			return nil, err
+		}
+
+		push(c, start.flag)
+	}
+
+	messages := []string{}
+	logged := map[plumbing.Hash]bool{}
+	for queue.Len() > 0 && !onlyExcluded(*queue, flags) {
+		c := heap.Pop(queue).(*walkItem).commit
+		flag := flags[c.Hash]
+		if flag == walkSource && !logged[c.Hash] {
+			logged[c.Hash] = true
+			messages = append(messages, strings.TrimSpace(c.Message))
+		}
+
+		err := c.Parents().ForEach(func(p *object.Commit) error {
+			push(p, flag)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return messages, nil
+}
+
+// onlyExcluded reports whether all the queued commits are reachable from the
+// excluded commit, none of their ancestors can be logged then
+func onlyExcluded(queue walkQueue, flags map[plumbing.Hash]int) bool {
+	for _, item := range queue {
+		if flags[item.commit.Hash]&walkExcluded == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (git *GoGit) GetCurrentBranch() (string, error) {
 	return git.Git.GetCheckedOutBranchShortName()
 }
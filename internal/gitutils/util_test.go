@@ -3,8 +3,13 @@ package gitutils
 import (
 	"preq/internal/pkg/client"
 	"testing"
+	"time"
 
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -41,7 +46,7 @@ func Test_getRemoteInfoList(t *testing.T) {
 			Git: &MockGitRepository{
 				ErrorValue: vErr,
 			},
-		})
+		}, nil)
 		assert.EqualError(t, err, vErr.Error())
 	})
 
@@ -52,24 +57,26 @@ func Test_getRemoteInfoList(t *testing.T) {
 			Git: &MockGitRepository{
 				ErrorValue: vErr,
 			},
-		})
+		}, nil)
 		assert.EqualError(t, err, vErr.Error())
 	})
 
 	t.Run("fails when cannot parse", func(t *testing.T) {
 		vErr := errors.New("parse err")
-		parseRepositoryString = func(repoString string) (*client.Repository, error) { return nil, vErr }
+		parseRepositoryString = func(repoString string, aliases map[client.RepositoryProvider][]string) (*client.Repository, error) {
+			return nil, vErr
+		}
 
 		_, err := getRemoteInfoList(&GoGit{
 			Git: &MockGitRepository{
 				RemoteURLsValue: []string{"url"},
 			},
-		})
+		}, nil)
 		assert.EqualError(t, err, vErr.Error())
 	})
 
 	t.Run("succeeds otherwise", func(t *testing.T) {
-		parseRepositoryString = func(repoString string) (*client.Repository, error) {
+		parseRepositoryString = func(repoString string, aliases map[client.RepositoryProvider][]string) (*client.Repository, error) {
 			return &client.Repository{}, nil
 		}
 
@@ -77,7 +84,7 @@ func Test_getRemoteInfoList(t *testing.T) {
 			Git: &MockGitRepository{
 				RemoteURLsValue: []string{"url"},
 			},
-		})
+		}, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(repos))
 	})
@@ -91,13 +98,39 @@ func Test_parseRemoteRepositoryURI(t *testing.T) {
 		assert.EqualError(t, err, ErrUnableToParseRemoteRepositoryURI.Error())
 	})
 
+	t.Run("fails on a local path", func(t *testing.T) {
+		_, err := extractRepositoryTokens("/home/user/repo")
+		assert.EqualError(t, err, ErrUnableToParseRemoteRepositoryURI.Error())
+	})
+
 	t.Run("succeeds on Bitbucket cloud SSH URI", func(t *testing.T) {
 		v, err := extractRepositoryTokens("git@provider:owner/repo.git")
 		assert.NoError(t, err)
-		assert.Equal(t, 3, len(v))
-		assert.Equal(t, "provider", v[0])
-		assert.Equal(t, "owner", v[1])
-		assert.Equal(t, "repo", v[2])
+		assert.Equal(t, []string{"provider", "owner/repo"}, v)
+	})
+
+	t.Run("succeeds on HTTPS URI", func(t *testing.T) {
+		v, err := extractRepositoryTokens("https://user@provider/owner/repo.git")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"provider", "owner/repo"}, v)
+	})
+
+	t.Run("succeeds on HTTPS URI without the .git suffix", func(t *testing.T) {
+		v, err := extractRepositoryTokens("https://provider/owner/repo")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"provider", "owner/repo"}, v)
+	})
+
+	t.Run("succeeds on SSH URI with a port", func(t *testing.T) {
+		v, err := extractRepositoryTokens("ssh://git@provider:7999/owner/repo.git")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"provider", "owner/repo"}, v)
+	})
+
+	t.Run("keeps nested groups", func(t *testing.T) {
+		v, err := extractRepositoryTokens("git@gitlab.com:group/subgroup/repo.git")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"gitlab.com", "group/subgroup/repo"}, v)
 	})
 }
 
@@ -108,39 +141,39 @@ func Test_parseRepositoryString(t *testing.T) {
 	t.Run("fails when cannot parse remote", func(t *testing.T) {
 		vErr := errors.New("remote err")
 		extractRepositoryTokens = func(uri string) ([]string, error) { return nil, vErr }
-		_, err := parseRepositoryString("")
+		_, err := parseRepositoryString("", nil)
 		assert.EqualError(t, err, vErr.Error())
 	})
 
 	t.Run("fails when cannot parse remote", func(t *testing.T) {
 		vErr := errors.New("provider err")
-		extractRepositoryTokens = func(uri string) ([]string, error) { return []string{""}, nil }
-		parseRepositoryProvider = func(p string) (client.RepositoryProvider, error) {
+		extractRepositoryTokens = func(uri string) ([]string, error) { return []string{"", ""}, nil }
+		parseRepositoryProvider = func(p string, aliases map[client.RepositoryProvider][]string) (client.RepositoryProvider, error) {
 			return client.RepositoryProviderEnum.BITBUCKET, vErr
 		}
 
-		_, err := parseRepositoryString("")
+		_, err := parseRepositoryString("", nil)
 		assert.EqualError(t, err, vErr.Error())
 	})
 
 	t.Run("fails when cannot parse provider", func(t *testing.T) {
 		vErr := errors.New("provider err")
-		extractRepositoryTokens = func(uri string) ([]string, error) { return []string{""}, nil }
-		parseRepositoryProvider = func(p string) (client.RepositoryProvider, error) {
+		extractRepositoryTokens = func(uri string) ([]string, error) { return []string{"", ""}, nil }
+		parseRepositoryProvider = func(p string, aliases map[client.RepositoryProvider][]string) (client.RepositoryProvider, error) {
 			return client.RepositoryProviderEnum.BITBUCKET, vErr
 		}
 
-		_, err := parseRepositoryString("")
+		_, err := parseRepositoryString("", nil)
 		assert.EqualError(t, err, vErr.Error())
 	})
 
 	t.Run("succeeds otherwise", func(t *testing.T) {
-		extractRepositoryTokens = func(uri string) ([]string, error) { return []string{"", "owner", "repo"}, nil }
-		parseRepositoryProvider = func(p string) (client.RepositoryProvider, error) {
+		extractRepositoryTokens = func(uri string) ([]string, error) { return []string{"", "owner/repo"}, nil }
+		parseRepositoryProvider = func(p string, aliases map[client.RepositoryProvider][]string) (client.RepositoryProvider, error) {
 			return client.RepositoryProviderEnum.BITBUCKET, nil
 		}
 
-		v, err := parseRepositoryString("")
+		v, err := parseRepositoryString("", nil)
 		assert.NoError(t, err)
 		assert.Equal(t, client.RepositoryProviderEnum.BITBUCKET, v.Provider)
 		assert.Equal(t, "owner/repo", v.Name)
@@ -155,21 +188,21 @@ func TestGetRemoteInfo(t *testing.T) {
 
 	t.Run("fails when getRemoteInfoList fails", func(t *testing.T) {
 		vErr := errors.New("repos err")
-		getRemoteInfoList = func(git *GoGit) ([]*client.Repository, error) {
+		getRemoteInfoList = func(git *GoGit, aliases map[client.RepositoryProvider][]string) ([]*client.Repository, error) {
 			return nil, vErr
 		}
 		r := &GoGit{}
-		_, err := r.GetRemoteInfo()
+		_, err := r.GetRemoteInfo(nil)
 		assert.EqualError(t, err, vErr.Error())
 	})
 
 	t.Run("", func(t *testing.T) {
-		getRemoteInfoList = func(git *GoGit) ([]*client.Repository, error) {
+		getRemoteInfoList = func(git *GoGit, aliases map[client.RepositoryProvider][]string) ([]*client.Repository, error) {
 			return []*client.Repository{&client.Repository{}}, nil
 		}
 		r := &GoGit{}
 
-		repos, err := r.GetRemoteInfo()
+		repos, err := r.GetRemoteInfo(nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, repos)
 	})
@@ -251,3 +284,79 @@ func TestGetCurrentCommitMessage(t *testing.T) {
 		assert.Equal(t, msg, val)
 	})
 }
+
+func Test_parseRepositoryName(t *testing.T) {
+	t.Run("keeps the path of other providers", func(t *testing.T) {
+		v := parseRepositoryName(client.RepositoryProviderEnum.GITLAB, "scm/group/repo")
+		assert.Equal(t, "scm/group/repo", v)
+	})
+
+	t.Run("strips the scm prefix of Bitbucket Server", func(t *testing.T) {
+		v := parseRepositoryName(client.RepositoryProviderEnum.BITBUCKET_SERVER, "bitbucket/scm/PROJ/repo")
+		assert.Equal(t, "PROJ/repo", v)
+	})
+
+	t.Run("keeps SSH paths of Bitbucket Server", func(t *testing.T) {
+		v := parseRepositoryName(client.RepositoryProviderEnum.BITBUCKET_SERVER, "PROJ/repo")
+		assert.Equal(t, "PROJ/repo", v)
+	})
+}
+
+func Test_logMessages(t *testing.T) {
+	t.Run("stops at the merge base", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		assert.NoError(t, err)
+		wt, err := repo.Worktree()
+		assert.NoError(t, err)
+
+		commit := func(msg string) plumbing.Hash {
+			h, err := wt.Commit(msg, &git.CommitOptions{
+				AllowEmptyCommits: true,
+				Author: &object.Signature{
+					Name: "preq",
+					When: time.Now(),
+				},
+			})
+			assert.NoError(t, err)
+			return h
+		}
+
+		base := commit("base")
+		commit("first")
+		head := commit("second\n\nbody\n")
+
+		messages, err := logMessages(repo, head, base)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"second\n\nbody", "first"}, messages)
+	})
+
+	t.Run("includes the commits of every merge parent", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		assert.NoError(t, err)
+		wt, err := repo.Worktree()
+		assert.NoError(t, err)
+
+		when := time.Now()
+		commit := func(msg string, parents ...plumbing.Hash) plumbing.Hash {
+			when = when.Add(time.Minute)
+			h, err := wt.Commit(msg, &git.CommitOptions{
+				AllowEmptyCommits: true,
+				Parents:           parents,
+				Author:            &object.Signature{Name: "preq", When: when},
+			})
+			assert.NoError(t, err)
+			return h
+		}
+
+		base := commit("base")
+		destination := commit("destination", base)
+		feature := commit("feature", base)
+		side := commit("side", base)
+		merged := commit("shared", destination)
+		head := commit("merge", feature, side, merged)
+
+		messages, err := logMessages(repo, head, merged)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"merge", "side", "feature"}, messages)
+	})
+}
@@ -0,0 +1,149 @@
+package gitutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"preq/internal/pkg/client"
+	"strings"
+)
+
+const worktreePrefix = "pr-"
+
+type Worktree struct {
+	Path string
+	// Head is the checked out commit
+	Head string
+}
+
+// WorktreePath returns the path of the pull request's worktree below the
+// worktree directory, e.g. <dir>/github/owner/repo/pr-7
+func WorktreePath(dir string, repo *client.Repository, id string) string {
+	return filepath.Join(
+		dir,
+		string(repo.Provider),
+		filepath.FromSlash(repo.Name),
+		worktreePrefix+id,
+	)
+}
+
+// WorktreePullRequestID returns the ID of the pull request of a worktree
+// created by AddPullRequestWorktree
+func WorktreePullRequestID(dir string, repo *client.Repository, path string) (string, bool) {
+	parent := filepath.Dir(WorktreePath(dir, repo, ""))
+	if !samePath(filepath.Dir(filepath.Clean(path)), parent) {
+		return "", false
+	}
+
+	name := filepath.Base(path)
+	if !strings.HasPrefix(name, worktreePrefix) || name == worktreePrefix {
+		return "", false
+	}
+
+	return strings.TrimPrefix(name, worktreePrefix), true
+}
+
+// samePath compares the paths with the symbolic links resolved, git reports
+// the resolved paths of the worktrees
+func samePath(a string, b string) bool {
+	if a == b {
+		return true
+	}
+
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && ra == rb
+}
+
+// ListWorktrees returns the linked worktrees of the repository at dir,
+// without the main one
+func ListWorktrees(dir string) ([]*Worktree, error) {
+	output, err := runGit(dir, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees := []*Worktree{}
+	// The entries are separated by empty lines, the first one is the main
+	// worktree
+	for i, entry := range strings.Split(output, "\n\n") {
+		if i == 0 {
+			continue
+		}
+
+		w := &Worktree{}
+		for _, line := range strings.Split(entry, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				w.Path = value
+			case "HEAD":
+				w.Head = value
+			}
+		}
+
+		if w.Path != "" {
+			worktrees = append(worktrees, w)
+		}
+	}
+
+	return worktrees, nil
+}
+
+// AddPullRequestWorktree fetches the pull request's source and checks out
+// its commit in a detached worktree at path. An existing worktree is moved
+// to the latest commit unless it has uncommitted changes.
+func AddPullRequestWorktree(
+	dir string,
+	path string,
+	repo *client.Repository,
+	pr *client.PullRequest,
+) error {
+	remote, ref, err := fetchSource(dir, repo, pr)
+	if err != nil {
+		return err
+	}
+
+	_, err = runGit(dir, "fetch", remote, ref)
+	if err != nil {
+		return err
+	}
+
+	hash, err := runGit(dir, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		dirty, err := isWorktreeDirty(path)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("the worktree at %s has uncommitted changes", path)
+		}
+
+		_, err = runGit(path, "checkout", "--quiet", "--detach", hash)
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	_, err = runGit(dir, "worktree", "add", "--detach", path, hash)
+	return err
+}
+
+// RemoveWorktree removes the linked worktree at path, uncommitted changes
+// are only discarded with force
+func RemoveWorktree(dir string, path string, force bool) error {
+	args := []string{"worktree", "remove", path}
+	if force {
+		args = append(args, "--force")
+	}
+
+	_, err := runGit(dir, args...)
+	return err
+}
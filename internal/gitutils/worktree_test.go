@@ -0,0 +1,69 @@
+package gitutils
+
+import (
+	"os"
+	"path/filepath"
+	"preq/internal/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddPullRequestWorktree(t *testing.T) {
+	upstream, clone := newUpstream(t)
+	dir := t.TempDir()
+	repo := &client.Repository{Provider: client.RepositoryProviderEnum.GITHUB, Name: "owner/repo"}
+	pr := &client.PullRequest{ID: "7", Source: client.PullRequestBranch{Name: "feature"}}
+	path := WorktreePath(dir, repo, pr.ID)
+
+	err := AddPullRequestWorktree(clone, path, repo, pr)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, runTestGit(t, upstream, "rev-parse", "feature"), runTestGit(t, path, "rev-parse", "HEAD"))
+	assert.Equal(t, "main", runTestGit(t, clone, "branch", "--show-current"))
+
+	worktrees, err := ListWorktrees(clone)
+	assert.NoError(t, err)
+	if assert.Len(t, worktrees, 1) {
+		id, ok := WorktreePullRequestID(dir, repo, worktrees[0].Path)
+		assert.True(t, ok)
+		assert.Equal(t, "7", id)
+	}
+
+	runTestGit(t, upstream, "switch", "-q", "feature")
+	runTestGit(t, upstream, "commit", "-q", "--allow-empty", "-m", "update")
+	runTestGit(t, upstream, "update-ref", "refs/pull/7/head", "feature")
+
+	err = AddPullRequestWorktree(clone, path, repo, pr)
+	assert.NoError(t, err)
+	assert.Equal(t, runTestGit(t, upstream, "rev-parse", "feature"), runTestGit(t, path, "rev-parse", "HEAD"))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(path, "file.txt"), []byte("a"), 0o644))
+	runTestGit(t, path, "add", "file.txt")
+	assert.Error(t, AddPullRequestWorktree(clone, path, repo, pr))
+	assert.Error(t, RemoveWorktree(clone, path, false))
+
+	assert.NoError(t, RemoveWorktree(clone, path, true))
+	worktrees, err = ListWorktrees(clone)
+	assert.NoError(t, err)
+	assert.Empty(t, worktrees)
+}
+
+func TestWorktreePullRequestID(t *testing.T) {
+	repo := &client.Repository{Provider: client.RepositoryProviderEnum.GITLAB, Name: "group/sub/repo"}
+
+	id, ok := WorktreePullRequestID("/worktrees", repo, "/worktrees/gitlab/group/sub/repo/pr-12")
+	assert.True(t, ok)
+	assert.Equal(t, "12", id)
+
+	for _, path := range []string{
+		"/worktrees/gitlab/group/sub/repo/pr-",
+		"/worktrees/gitlab/group/sub/repo/feature",
+		"/worktrees/gitlab/group/other/pr-12",
+		"/elsewhere/pr-12",
+	} {
+		_, ok := WorktreePullRequestID("/worktrees", repo, path)
+		assert.False(t, ok, path)
+	}
+}
@@ -0,0 +1,79 @@
+package logutils
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tidwall/gjson"
+)
+
+type Entry struct {
+	Time    time.Time
+	Level   zerolog.Level
+	Message string
+	Error   string
+}
+
+// Buffer keeps the latest log entries in memory, e.g. for showing them
+// in the TUI. It is written to by the logger next to the log file.
+type Buffer struct {
+	mu        sync.Mutex
+	size      int
+	entries   []*Entry
+	listeners []func(*Entry)
+}
+
+func NewBuffer(size int) *Buffer {
+	return &Buffer{size: size}
+}
+
+// Write parses the JSON encoded log event written by zerolog
+func (b *Buffer) Write(p []byte) (int, error) {
+	event := gjson.ParseBytes(p)
+	level, err := zerolog.ParseLevel(event.Get(zerolog.LevelFieldName).String())
+	if err != nil {
+		level = zerolog.NoLevel
+	}
+
+	entry := &Entry{
+		Time:    event.Get(zerolog.TimestampFieldName).Time(),
+		Level:   level,
+		Message: event.Get(zerolog.MessageFieldName).String(),
+		Error:   event.Get(zerolog.ErrorFieldName).String(),
+	}
+
+	b.mu.Lock()
+	b.entries = append(b.entries, entry)
+	if len(b.entries) > b.size {
+		b.entries = b.entries[len(b.entries)-b.size:]
+	}
+	listeners := b.listeners
+	b.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(entry)
+	}
+
+	return len(p), nil
+}
+
+// Entries returns a copy of the buffered entries, oldest first
+func (b *Buffer) Entries() []*Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entries := make([]*Entry, len(b.entries))
+	copy(entries, b.entries)
+
+	return entries
+}
+
+// Subscribe registers a callback for every new entry, the callback is run
+// on the goroutine which logged the entry
+func (b *Buffer) Subscribe(fn func(*Entry)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.listeners = append(b.listeners, fn)
+}
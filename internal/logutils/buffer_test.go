@@ -0,0 +1,31 @@
+package logutils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuffer(t *testing.T) {
+	b := NewBuffer(2)
+	notified := 0
+	b.Subscribe(func(_ *Entry) { notified++ })
+
+	logger := zerolog.New(b)
+	logger.Info().Msg("first")
+	logger.Warn().Msg("second")
+	logger.Error().Err(errors.New("not found")).Msg("request failed")
+
+	entries := b.Entries()
+	assert.Equal(t, 3, notified)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, zerolog.WarnLevel, entries[0].Level)
+	assert.Equal(t, &Entry{
+		Level:   zerolog.ErrorLevel,
+		Message: "request failed",
+		Error:   "not found",
+		Time:    entries[1].Time,
+	}, entries[1])
+}
@@ -0,0 +1,67 @@
+package logutils
+
+import (
+	"io"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultLevel   = zerolog.ErrorLevel
+	defaultLogFile = "~/.local/state/preq/app.log"
+	maxFileSize    = 10 * 1024 * 1024
+	maxBackups     = 3
+	bufferSize     = 1000
+)
+
+// Entries holds the latest log entries of the global logger
+var Entries = NewBuffer(bufferSize)
+
+type Options struct {
+	Level string
+	File  string
+}
+
+// Setup configures the global logger to write to the rotated log file and
+// the in-memory buffer, the returned file has to be closed by the caller
+func Setup(o *Options) (io.Closer, error) {
+	path := o.File
+	if path == "" {
+		path = defaultLogFile
+	}
+
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := OpenRotatingFile(path, maxFileSize, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	w := zerolog.MultiLevelWriter(
+		zerolog.ConsoleWriter{
+			Out:        file,
+			TimeFormat: time.RFC3339,
+		},
+		Entries,
+	)
+	log.Logger = zerolog.New(w).With().Timestamp().Logger()
+
+	zerolog.SetGlobalLevel(DefaultLevel)
+	if o.Level != "" {
+		level, err := zerolog.ParseLevel(o.Level)
+		if err != nil {
+			log.Error().
+				Msgf("unknown log level '%v', reverting to default %s level", o.Level, DefaultLevel)
+		} else {
+			zerolog.SetGlobalLevel(level)
+		}
+	}
+
+	return file, nil
+}
@@ -0,0 +1,109 @@
+package logutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile is a log file which is rotated once it grows over the
+// maximum size, the rotated files are suffixed with .1, .2, etc.
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file, f.size = file, info.Size()
+
+	return nil
+}
+
+func (f *RotatingFile) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", f.path, i)
+}
+
+// rotate shifts the backups by one, dropping the oldest, and starts
+// a new file
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	if err != nil {
+		return err
+	}
+
+	os.Remove(f.backupPath(f.maxBackups))
+	for i := f.maxBackups - 1; i > 0; i-- {
+		os.Rename(f.backupPath(i), f.backupPath(i+1))
+	}
+
+	if f.maxBackups > 0 {
+		err = os.Rename(f.path, f.backupPath(1))
+	} else {
+		err = os.Remove(f.path)
+	}
+	if err != nil {
+		return err
+	}
+
+	return f.open()
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		err := f.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Close()
+}
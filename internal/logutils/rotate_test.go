@@ -0,0 +1,43 @@
+package logutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	f, err := OpenRotatingFile(path, 10, 2)
+	assert.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = f.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, f.Close())
+
+	read := func(p string) string {
+		content, _ := os.ReadFile(p)
+		return string(content)
+	}
+	assert.Equal(t, "fourth\n", read(path))
+	assert.Equal(t, "third\n", read(path+".1"))
+	assert.Equal(t, "second\n", read(path+".2"))
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestRotatingFile_appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte("old\n"), 0o600)
+
+	f, err := OpenRotatingFile(path, 100, 1)
+	assert.NoError(t, err)
+	f.Write([]byte("new\n"))
+	f.Close()
+
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, "old\nnew\n", string(content))
+}
@@ -17,14 +17,27 @@ type PersistanceRepoInfo struct {
 	Path        string    `json:"path,omitempty"`
 }
 
+// PersistanceUserInfo is the authenticated user of a provider account,
+// cached to avoid resolving it on every run
+type PersistanceUserInfo struct {
+	Key      string    `json:"key"`
+	ID       string    `json:"id"`
+	Username string    `json:"username,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Updated  time.Time `json:"updated"`
+}
+
 type state struct {
 	Visited []*PersistanceRepoInfo `json:"visited,omitempty"`
+	Users   []*PersistanceUserInfo `json:"users,omitempty"`
 }
 
 type PersistanceRepo interface {
 	AddVisited(name string, provider string, path string) error
 	GetVisited() ([]*PersistanceRepoInfo, error)
 	GetInfo(name string, provider string) (*PersistanceRepoInfo, error)
+	GetUser(key string) (*PersistanceUserInfo, error)
+	SetUser(info *PersistanceUserInfo) error
 }
 
 type XDGPersistanceRepo struct {
@@ -161,6 +174,42 @@ func (repo *XDGPersistanceRepo) AddVisited(
 	return err
 }
 
+func (repo *XDGPersistanceRepo) GetUser(key string) (*PersistanceUserInfo, error) {
+	err := repo.load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range repo.s.Users {
+		if u.Key == key {
+			return u, nil
+		}
+	}
+
+	return nil, fmt.Errorf("user not found")
+}
+
+func (repo *XDGPersistanceRepo) SetUser(info *PersistanceUserInfo) error {
+	err := repo.load()
+	if err != nil {
+		return err
+	}
+
+	info.Updated = time.Now()
+	index := slices.IndexFunc(
+		repo.s.Users,
+		func(v *PersistanceUserInfo) bool { return v.Key == info.Key },
+	)
+
+	if index != -1 {
+		repo.s.Users[index] = info
+	} else {
+		repo.s.Users = append(repo.s.Users, info)
+	}
+
+	return repo.save()
+}
+
 var persistanceRepo PersistanceRepo = &XDGPersistanceRepo{
 	s: &state{},
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"preq/internal/pkg/client"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -227,6 +228,21 @@ func (c *BitbucketCloudClient) DeleteComment(
 	return nil
 }
 
+// GetDiff returns the diff of the pull request's source with its merge base,
+// the endpoint redirects to the diff of the commits
+func (c *BitbucketCloudClient) GetDiff(o *client.GetDiffOptions) ([]byte, error) {
+	r, err := c.get(fmt.Sprintf(
+		"https://api.bitbucket.org/2.0/repositories/%s/pullrequests/%s/diff",
+		o.Repository.Name,
+		o.ID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Body(), nil
+}
+
 func (c *BitbucketCloudClient) GetComments(
 	options *client.GetCommentsOptions,
 ) ([]*client.PullRequestComment, error) {
@@ -297,6 +313,86 @@ func (c *BitbucketCloudClient) GetComments(
 	return iter.GetAll()
 }
 
+// stateQueryParams returns the repeated state parameter, Bitbucket only
+// lists the open pull requests without it
+func stateQueryParams(s client.PullRequestState) []string {
+	if s != "" {
+		return []string{string(s)}
+	}
+
+	return []string{
+		client.PullRequestState_OPEN,
+		client.PullRequestState_MERGED,
+		client.PullRequestState_DECLINED,
+		client.PullRequestState_SUPERSEDED,
+	}
+}
+
+// userQuery matches the user by the UUID or by the nickname
+func userQuery(field, user string) string {
+	if strings.HasPrefix(user, "{") {
+		return fmt.Sprintf("%s.uuid = %s", field, strconv.Quote(user))
+	}
+
+	return fmt.Sprintf("%s.nickname = %s", field, strconv.Quote(user))
+}
+
+// filterQuery builds the BBQL query of the filter, see
+// https://developer.atlassian.com/cloud/bitbucket/rest/intro/#filtering
+func filterQuery(f *client.PullRequestFilter) string {
+	if f == nil {
+		return ""
+	}
+
+	parts := []string{}
+	if f.Author != "" {
+		parts = append(parts, userQuery("author", f.Author))
+	}
+	if f.Reviewer != "" {
+		parts = append(parts, userQuery("reviewers", f.Reviewer))
+	}
+	if f.Source != "" {
+		parts = append(parts, fmt.Sprintf("source.branch.name = %s", strconv.Quote(f.Source)))
+	}
+	if f.Destination != "" {
+		parts = append(parts, fmt.Sprintf("destination.branch.name = %s", strconv.Quote(f.Destination)))
+	}
+	if !f.UpdatedSince.IsZero() {
+		parts = append(parts, fmt.Sprintf("updated_on >= %s", f.UpdatedSince.UTC().Format(time.RFC3339)))
+	}
+	if f.Search != "" {
+		search := strconv.Quote(f.Search)
+		parts = append(parts, fmt.Sprintf("(title ~ %s OR description ~ %s)", search, search))
+	}
+
+	return strings.Join(parts, " AND ")
+}
+
+func parsePullRequest(value gjson.Result) *client.PullRequest {
+	return &client.PullRequest{
+		Description:  value.Get("description").String(),
+		ID:           value.Get("id").String(),
+		CommentCount: int(value.Get("comment_count").Float()),
+		Title:        value.Get("title").String(),
+		User:         value.Get("author.nickname").String(),
+		URL:          value.Get("links.html.href").String(),
+		State:        client.PullRequestState(value.Get("state").String()),
+		IsDraft:      value.Get("draft").Bool(),
+		Source: client.PullRequestBranch{
+			Name:       value.Get("source.branch.name").String(),
+			Hash:       value.Get("source.commit.hash").String(),
+			Repository: value.Get("source.repository.full_name").String(),
+		},
+		Destination: client.PullRequestBranch{
+			Name:       value.Get("destination.branch.name").String(),
+			Hash:       value.Get("destination.commit.hash").String(),
+			Repository: value.Get("destination.repository.full_name").String(),
+		},
+		Created: value.Get("created_on").Time(),
+		Updated: value.Get("updated_on").Time(),
+	}
+}
+
 func (c *BitbucketCloudClient) GetPullRequests(
 	o *client.GetPullRequestsOptions,
 ) (*client.PullRequestList, error) {
@@ -305,16 +401,23 @@ func (c *BitbucketCloudClient) GetPullRequests(
 		o.Repository.Name,
 	)
 
+	request := resty.New().R().
+		SetBasicAuth(c.username, c.password).
+		SetError(bbError{})
+
+	// The next page URL already contains all the query parameters
 	if o.Next != "" {
 		url = o.Next
+	} else {
+		request.SetQueryParamsFromValues(map[string][]string{
+			"state": stateQueryParams(o.State),
+		})
+		if q := filterQuery(o.Filter); q != "" {
+			request.SetQueryParam("q", q)
+		}
 	}
 
-	rc := resty.New()
-	r, err := rc.R().
-		SetBasicAuth(c.username, c.password).
-		SetQueryParam("state", string(o.State)).
-		SetError(bbError{}).
-		Get(url)
+	r, err := request.Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -330,32 +433,77 @@ func (c *BitbucketCloudClient) GetPullRequests(
 	pr.NextURL = parsed.Get("next").String()
 	result := parsed.Get("values")
 	result.ForEach(func(key, value gjson.Result) bool {
-		pr.Values = append(pr.Values, &client.PullRequest{
-			Description:  value.Get("description").String(),
-			ID:           value.Get("id").String(),
-			CommentCount: int(value.Get("comment_count").Float()),
-			Title:        value.Get("title").String(),
-			User:         value.Get("author.nickname").String(),
-			URL:          value.Get("links.html.href").String(),
-			State:        client.PullRequestState(value.Get("state").String()),
-			Source: client.PullRequestBranch{
-				Name: value.Get("source.branch.name").String(),
-				Hash: value.Get("source.commit.hash").String(),
-			},
-			Destination: client.PullRequestBranch{
-				Name: value.Get("destination.branch.name").String(),
-				Hash: value.Get("destination.commit.hash").String(),
-			},
-			Created: value.Get("created_on").Time(),
-			Updated: value.Get("updated_on").Time(),
-		})
-
+		pr.Values = append(pr.Values, parsePullRequest(value))
 		return true
 	})
 
 	return &pr, nil
 }
 
+// awaitsReview reports whether the reviewer has not approved the pull
+// request yet, the participants are only listed when requested in fields
+func awaitsReview(value gjson.Result, user *client.User) bool {
+	for _, p := range value.Get("participants").Array() {
+		if user.Matches(p.Get("user.uuid").String()) && p.Get("approved").Bool() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetReviewRequests lists the open pull requests where the user is a
+// reviewer and has not approved them yet
+func (c *BitbucketCloudClient) GetReviewRequests(
+	o *client.GetReviewRequestsOptions,
+) ([]*client.PullRequest, error) {
+	user := o.User
+	if user == nil {
+		var err error
+		user, err = c.GetCurrentUser()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	values := []*client.PullRequest{}
+	url := fmt.Sprintf(
+		"https://api.bitbucket.org/2.0/repositories/%s/pullrequests",
+		o.Repository.Name,
+	)
+	request := resty.New().R().
+		SetBasicAuth(c.username, c.password).
+		SetError(bbError{}).
+		SetQueryParams(map[string]string{
+			"state":  client.PullRequestState_OPEN,
+			"q":      userQuery("reviewers", user.ID),
+			"fields": "+values.participants",
+		})
+
+	for url != "" {
+		r, err := request.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		if r.IsError() {
+			return nil, errors.New(string(r.Body()))
+		}
+
+		parsed := gjson.ParseBytes(r.Body())
+		for _, value := range parsed.Get("values").Array() {
+			if awaitsReview(value, user) {
+				values = append(values, parsePullRequest(value))
+			}
+		}
+
+		// The next page URL already contains all the query parameters
+		url = parsed.Get("next").String()
+		request.QueryParam = map[string][]string{}
+	}
+
+	return values, nil
+}
+
 func unmarshalPR(data []byte) (*client.PullRequest, error) {
 	pr := &bitbucketPullRequest{}
 	err := json.Unmarshal(data, pr)
@@ -364,17 +512,21 @@ func unmarshalPR(data []byte) (*client.PullRequest, error) {
 	}
 
 	return &client.PullRequest{
-		ID:    fmt.Sprint(pr.ID),
-		Title: pr.Title,
-		URL:   pr.Links.HTML.Href,
-		State: pr.State,
+		ID:          fmt.Sprint(pr.ID),
+		Title:       pr.Title,
+		Description: pr.Description,
+		URL:         pr.Links.HTML.Href,
+		State:       pr.State,
+		IsDraft:     pr.Draft,
 		Source: client.PullRequestBranch{
-			Name: pr.Source.Branch.Name,
-			Hash: pr.Source.Commit.Hash,
+			Name:       pr.Source.Branch.Name,
+			Hash:       pr.Source.Commit.Hash,
+			Repository: pr.Source.Repository.FullName,
 		},
 		Destination: client.PullRequestBranch{
-			Name: pr.Destination.Branch.Name,
-			Hash: pr.Destination.Commit.Hash,
+			Name:       pr.Destination.Branch.Name,
+			Hash:       pr.Destination.Commit.Hash,
+			Repository: pr.Destination.Repository.FullName,
 		},
 	}, nil
 }
@@ -439,11 +591,129 @@ func (c *BitbucketCloudClient) Merge(
 		o.ID,
 	)
 
-	r, err := c.post(url)
+	r, err := resty.New().R().
+		SetBasicAuth(c.username, c.password).
+		SetHeader("content-type", "application/json").
+		SetBody(bbMergeOptions{
+			Message:       o.Message,
+			MergeStrategy: string(o.Strategy),
+		}).
+		SetError(bbError{}).
+		Post(url)
 	if err != nil {
 		return nil, err
 	}
 
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
+
+	return unmarshalPR(r.Body())
+}
+
+// matchesReviewer checks whether the reviewer is identified by the id,
+// which is either the UUID, the account ID or the nickname
+func matchesReviewer(reviewer gjson.Result, id string) bool {
+	return reviewer.Get("uuid").String() == id ||
+		reviewer.Get("account_id").String() == id ||
+		reviewer.Get("nickname").String() == id
+}
+
+// newReviewer returns the reviewer reference of the id, UUIDs are
+// wrapped in curly braces while account IDs are not
+func newReviewer(id string) bbReviewer {
+	if strings.HasPrefix(id, "{") {
+		return bbReviewer{UUID: id}
+	}
+
+	return bbReviewer{AccountID: id}
+}
+
+func updateReviewers(
+	current gjson.Result,
+	add []string,
+	remove []string,
+) []bbReviewer {
+	reviewers := []bbReviewer{}
+	current.ForEach(func(key, value gjson.Result) bool {
+		for _, id := range remove {
+			if matchesReviewer(value, id) {
+				return true
+			}
+		}
+
+		reviewers = append(reviewers, bbReviewer{UUID: value.Get("uuid").String()})
+		return true
+	})
+
+	for _, id := range add {
+		exists := false
+		current.ForEach(func(key, value gjson.Result) bool {
+			exists = matchesReviewer(value, id)
+			return !exists
+		})
+
+		if !exists {
+			reviewers = append(reviewers, newReviewer(id))
+		}
+	}
+
+	return reviewers
+}
+
+func (c *BitbucketCloudClient) UpdatePullRequest(
+	o *client.UpdatePullRequestOptions,
+) (*client.PullRequest, error) {
+	url := fmt.Sprintf(
+		"https://api.bitbucket.org/2.0/repositories/%s/pullrequests/%s",
+		o.Repository.Name,
+		o.ID,
+	)
+
+	// The current pull request is needed for the required title and
+	// the reviewers, which are replaced as a whole
+	r, err := c.get(url)
+	if err != nil {
+		return nil, err
+	}
+	current := gjson.ParseBytes(r.Body())
+
+	options := bbPRUpdateOptions{
+		Title:       current.Get("title").String(),
+		Description: o.Description,
+		Draft:       o.Draft,
+	}
+	if o.Title != nil {
+		options.Title = *o.Title
+	}
+	if o.Destination != nil {
+		options.Destination = &bbPRSourceOptions{
+			Branch: bbPRSourceBranchOptions{Name: *o.Destination},
+		}
+	}
+	if len(o.AddReviewers) > 0 || len(o.RemoveReviewers) > 0 {
+		reviewers := updateReviewers(
+			current.Get("reviewers"),
+			o.AddReviewers,
+			o.RemoveReviewers,
+		)
+		options.Reviewers = &reviewers
+	}
+
+	r, err = resty.New().R().
+		SetBasicAuth(c.username, c.password).
+		SetHeader("content-type", "application/json").
+		SetBody(options).
+		SetError(bbError{}).
+		Put(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
+
 	return unmarshalPR(r.Body())
 }
 
@@ -501,7 +771,16 @@ func (c *BitbucketCloudClient) Approve(
 func (c *BitbucketCloudClient) GetPullRequestInfo(
 	o *client.ApproveOptions,
 ) (*client.PullRequest, error) {
-	return nil, errors.New("not implemented")
+	r, err := c.get(fmt.Sprintf(
+		"https://api.bitbucket.org/2.0/repositories/%s/pullrequests/%s",
+		o.Repository.Name,
+		o.ID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalPR(r.Body())
 }
 
 func verifyCreatePullRequestOptions(o *client.CreatePullRequestOptions) error {
@@ -543,22 +822,80 @@ func (c *BitbucketCloudClient) GetDefaultReviewer(
 }
 
 func (c *BitbucketCloudClient) GetCurrentUser() (*client.User, error) {
-	r, err := resty.New().R().
-		SetBasicAuth(c.username, c.password).
-		SetResult(user{}).
-		SetError(bbError{}).
-		Get("https://api.bitbucket.org/2.0/user")
+	r, err := c.get("https://api.bitbucket.org/2.0/user")
 	if err != nil {
 		return nil, err
 	}
 
-	if r.IsError() {
-		return nil, errors.New(r.Error().(*bbError).Message)
+	return parseUser(gjson.ParseBytes(r.Body())), nil
+}
+
+// createReviewers deduplicates the reviewers and leaves out the author,
+// which Bitbucket refuses as a reviewer
+func createReviewers(ids []string, author string) []bbPROptionsReviewer {
+	reviewers := make([]bbPROptionsReviewer, 0, len(ids))
+	seen := map[string]bool{author: true}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			reviewers = append(reviewers, bbPROptionsReviewer{UUID: id})
+		}
 	}
 
+	return reviewers
+}
+
+func parseUser(value gjson.Result) *client.User {
 	return &client.User{
-		ID: r.Result().(*user).UUID,
-	}, nil
+		ID:       value.Get("uuid").String(),
+		Username: value.Get("nickname").String(),
+		Name:     value.Get("display_name").String(),
+	}
+}
+
+func matchesUser(u *client.User, query string) bool {
+	query = strings.ToLower(query)
+	for _, v := range []string{u.ID, u.Username, u.Name} {
+		if strings.Contains(strings.ToLower(v), query) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SearchUsers looks up the members of the repository's workspace, since
+// Bitbucket does not offer a user search
+func (c *BitbucketCloudClient) SearchUsers(
+	o *client.SearchUsersOptions,
+) ([]*client.User, error) {
+	workspace := strings.Split(o.Repository.Name, "/")[0]
+	iter := newBitbucketIterator(
+		&newBitbucketIteratorOptions[*client.User]{
+			Client: c,
+			RequestURL: fmt.Sprintf(
+				"https://api.bitbucket.org/2.0/workspaces/%s/members",
+				workspace,
+			),
+			Parse: func(key, value gjson.Result) (*client.User, error) {
+				return parseUser(value.Get("user")), nil
+			},
+		},
+	)
+
+	members, err := iter.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	users := []*client.User{}
+	for _, u := range members {
+		if matchesUser(u, o.Query) {
+			users = append(users, u)
+		}
+	}
+
+	return users, nil
 }
 
 func (c *BitbucketCloudClient) GetDefaultReviewers(
@@ -597,29 +934,36 @@ func (c *BitbucketCloudClient) CreatePullRequest(
 		return nil, err
 	}
 
-	dr, err := c.GetDefaultReviewers(o)
-	if err != nil {
-		return nil, err
+	dr := []*Reviewer{}
+	if !o.SkipDefaultReviewers {
+		dr, err = c.GetDefaultReviewers(o)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	ddr := make([]bbPROptionsReviewer, 0, len(dr))
+	ids := make([]string, 0, len(dr)+len(o.Reviewers))
 	for _, v := range dr {
-		if v.UUID != c.uuid {
-			ddr = append(ddr, bbPROptionsReviewer{UUID: v.UUID})
-		}
+		ids = append(ids, v.UUID)
 	}
 
-	if o.Draft {
-		o.Title = fmt.Sprintf("[DRAFT] %s", o.Title)
+	// The configured UUID overrides the detected user
+	author := c.uuid
+	if author == "" {
+		author = o.Author
 	}
 
+	ddr := createReviewers(append(ids, o.Reviewers...), author)
+
 	r, err := resty.New().R().
 		SetBasicAuth(c.username, c.password).
 		SetHeader("content-type", "application/json").
 		SetBody(bbPROptions{
 			Title:             o.Title,
+			Description:       o.Description,
 			CloseSourceBranch: o.CloseBranch,
 			Reviewers:         ddr,
+			Draft:             o.Draft,
 			Source: bbPRSourceOptions{
 				Branch: bbPRSourceBranchOptions{
 					Name: o.Source,
@@ -658,24 +1002,6 @@ func (c *BitbucketCloudClient) CreatePullRequest(
 		}
 		return nil, fmt.Errorf(string(r.Body()))
 	}
-	pr := &bitbucketPullRequest{}
-	err = json.Unmarshal(r.Body(), pr)
-	if err != nil {
-		return nil, err
-	}
 
-	return &client.PullRequest{
-		ID:    fmt.Sprint(pr.ID),
-		Title: pr.Title,
-		URL:   pr.Links.HTML.Href,
-		State: pr.State,
-		Source: client.PullRequestBranch{
-			Name: pr.Source.Branch.Name,
-			Hash: pr.Source.Commit.Hash,
-		},
-		Destination: client.PullRequestBranch{
-			Name: pr.Destination.Branch.Name,
-			Hash: pr.Destination.Commit.Hash,
-		},
-	}, nil
+	return unmarshalPR(r.Body())
 }
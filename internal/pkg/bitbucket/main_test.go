@@ -1,7 +1,83 @@
 package bitbucket
 
+import (
+	"preq/internal/pkg/client"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func Test_createReviewers(t *testing.T) {
+	t.Run("removes duplicates and the author", func(t *testing.T) {
+		v := createReviewers([]string{"{a}", "{b}", "{a}", "{me}"}, "{me}")
+		assert.Equal(t, []bbPROptionsReviewer{{UUID: "{a}"}, {UUID: "{b}"}}, v)
+	})
+}
+
 // func TestASD(t *testing.T) {
 // 	c := New()
 // 	c.GetPullRequests()
 // 	// assert.IsType(t, client, c)
 // }
+
+func Test_filterQuery(t *testing.T) {
+	assert.Equal(t, "", filterQuery(nil))
+	assert.Equal(
+		t,
+		`author.uuid = "{me}" AND reviewers.nickname = "alice" AND source.branch.name = "fix" AND `+
+			`destination.branch.name = "main" AND updated_on >= 2023-03-01T00:00:00Z AND `+
+			`(title ~ "say \"hi\"" OR description ~ "say \"hi\"")`,
+		filterQuery(&client.PullRequestFilter{
+			Author:       "{me}",
+			Reviewer:     "alice",
+			Source:       "fix",
+			Destination:  "main",
+			UpdatedSince: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			Search:       `say "hi"`,
+		}),
+	)
+}
+
+func Test_stateQueryParams(t *testing.T) {
+	assert.Equal(t, []string{"MERGED"}, stateQueryParams(client.PullRequestState_MERGED))
+	assert.Len(t, stateQueryParams(""), 4)
+}
+
+func Test_awaitsReview(t *testing.T) {
+	user := &client.User{ID: "{me}", Username: "me"}
+	value := gjson.Parse(`{"participants": [
+		{"user": {"uuid": "{other}"}, "approved": true},
+		{"user": {"uuid": "{me}"}, "role": "REVIEWER", "approved": false}
+	]}`)
+	assert.True(t, awaitsReview(value, user))
+
+	value = gjson.Parse(`{"participants": [
+		{"user": {"uuid": "{me}"}, "role": "REVIEWER", "approved": true}
+	]}`)
+	assert.False(t, awaitsReview(value, user))
+}
+
+func Test_unmarshalPR(t *testing.T) {
+	pr, err := unmarshalPR([]byte(`{
+		"id": 7,
+		"title": "Feature",
+		"state": "MERGED",
+		"source": {
+			"branch": {"name": "feature"},
+			"commit": {"hash": "abc"},
+			"repository": {"full_name": "fork/repo"}
+		},
+		"destination": {
+			"branch": {"name": "main"},
+			"commit": {"hash": "def"},
+			"repository": {"full_name": "owner/repo"}
+		}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "7", pr.ID)
+	assert.EqualValues(t, client.PullRequestState_MERGED, pr.State)
+	assert.Equal(t, client.PullRequestBranch{Name: "feature", Hash: "abc", Repository: "fork/repo"}, pr.Source)
+	assert.Equal(t, "owner/repo", pr.Destination.Repository)
+}
@@ -20,10 +20,30 @@ type bbPROptionsReviewer struct {
 
 type bbPROptions struct {
 	Title             string                `json:"title,omitempty"`
+	Description       string                `json:"description,omitempty"`
 	Source            bbPRSourceOptions     `json:"source,omitempty"`
 	Destination       bbPRSourceOptions     `json:"destination,omitempty"`
 	CloseSourceBranch bool                  `json:"close_source_branch,omitempty"`
 	Reviewers         []bbPROptionsReviewer `json:"reviewers"`
+	Draft             bool                  `json:"draft,omitempty"`
+}
+
+type bbReviewer struct {
+	UUID      string `json:"uuid,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
+}
+
+type bbPRUpdateOptions struct {
+	Title       string             `json:"title"`
+	Description *string            `json:"description,omitempty"`
+	Destination *bbPRSourceOptions `json:"destination,omitempty"`
+	Reviewers   *[]bbReviewer      `json:"reviewers,omitempty"`
+	Draft       *bool              `json:"draft,omitempty"`
+}
+
+type bbMergeOptions struct {
+	Message       string `json:"message,omitempty"`
+	MergeStrategy string `json:"merge_strategy,omitempty"`
 }
 
 type bbError struct {
@@ -61,6 +81,9 @@ type bitbucketPullRequest struct {
 		Commit struct {
 			Hash string
 		}
+		Repository struct {
+			FullName string `json:"full_name"`
+		}
 	}
 	Source struct {
 		Branch struct {
@@ -69,8 +92,12 @@ type bitbucketPullRequest struct {
 		Commit struct {
 			Hash string
 		}
+		Repository struct {
+			FullName string `json:"full_name"`
+		}
 	}
 	CloseSourceBranch bool `json:"close_source_branch"`
+	Draft             bool `json:"draft"`
 }
 
 type Reviewer struct {
@@ -0,0 +1,902 @@
+package bitbucketserver
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	preqClient "preq/internal/pkg/client"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/tidwall/gjson"
+)
+
+const pageLimit = 100
+
+var (
+	ErrInvalidRepositoryName = errors.New(strings.TrimSpace(`
+		invalid repository name, expected (PROJECT/repository)
+	`))
+	ErrUnknownCurrentUser = errors.New("unable to determine the current user")
+)
+
+type BitbucketServerClient struct {
+	token   string
+	baseURL string
+	// currentUser is the token's owner, resolved on first use. The mutex
+	// guards it, the pull requests of several repositories are loaded
+	// concurrently.
+	currentUserMutex sync.Mutex
+	currentUser      *preqClient.User
+}
+
+type ClientOptions struct {
+	Token string
+	// BaseURL is the URL of the Bitbucket Server instance,
+	// e.g. https://bitbucket.example.com
+	BaseURL string
+}
+
+func New(o *ClientOptions) preqClient.Client {
+	return &BitbucketServerClient{
+		token:   o.Token,
+		baseURL: strings.TrimSuffix(o.BaseURL, "/"),
+	}
+}
+
+func splitRepositoryName(name string) (string, string, error) {
+	v := strings.Split(name, "/")
+	if len(v) != 2 || v[0] == "" || v[1] == "" {
+		return "", "", ErrInvalidRepositoryName
+	}
+
+	return v[0], v[1], nil
+}
+
+func (c *BitbucketServerClient) repositoryURL(
+	repo *preqClient.Repository,
+) (string, error) {
+	project, slug, err := splitRepositoryName(repo.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"%s/rest/api/1.0/projects/%s/repos/%s",
+		c.baseURL,
+		url.PathEscape(project),
+		url.PathEscape(slug),
+	), nil
+}
+
+func (c *BitbucketServerClient) pullRequestURL(
+	repo *preqClient.Repository,
+	id string,
+) (string, error) {
+	u, err := c.repositoryURL(repo)
+	if err != nil {
+		return "", err
+	}
+
+	if id == "" {
+		return fmt.Sprintf("%s/pull-requests", u), nil
+	}
+
+	return fmt.Sprintf("%s/pull-requests/%s", u, id), nil
+}
+
+func (c *BitbucketServerClient) request() *resty.Request {
+	return resty.New().R().
+		SetAuthToken(c.token).
+		SetError(bbsError{})
+}
+
+func (c *BitbucketServerClient) send(
+	method string,
+	url string,
+	body interface{},
+) (*resty.Response, error) {
+	request := c.request()
+	if body != nil {
+		request.
+			SetHeader("content-type", "application/json").
+			SetBody(body)
+	}
+
+	r, err := request.Execute(method, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
+
+	return r, nil
+}
+
+func (c *BitbucketServerClient) get(url string) (*resty.Response, error) {
+	return c.send(resty.MethodGet, url, nil)
+}
+
+// getAll calls fn for every value of a paged response, following
+// `nextPageStart` until `isLastPage` is set
+func (c *BitbucketServerClient) getAll(
+	url string,
+	fn func(value gjson.Result),
+) error {
+	start := int64(0)
+	for {
+		r, err := c.request().
+			SetQueryParam("limit", fmt.Sprint(pageLimit)).
+			SetQueryParam("start", fmt.Sprint(start)).
+			Get(url)
+		if err != nil {
+			return err
+		}
+		if r.IsError() {
+			return errors.New(string(r.Body()))
+		}
+
+		parsed := gjson.ParseBytes(r.Body())
+		parsed.Get("values").ForEach(func(key, value gjson.Result) bool {
+			fn(value)
+			return true
+		})
+
+		if parsed.Get("isLastPage").Bool() || !parsed.Get("nextPageStart").Exists() {
+			return nil
+		}
+
+		start = parsed.Get("nextPageStart").Int()
+	}
+}
+
+func parseTime(value gjson.Result) time.Time {
+	return time.UnixMilli(value.Int())
+}
+
+func parsePullRequest(value gjson.Result) *preqClient.PullRequest {
+	return &preqClient.PullRequest{
+		ID:           value.Get("id").String(),
+		Title:        value.Get("title").String(),
+		Description:  value.Get("description").String(),
+		CommentCount: int(value.Get("properties.commentCount").Int()),
+		User:         value.Get("author.user.name").String(),
+		URL:          value.Get("links.self.0.href").String(),
+		State:        preqClient.PullRequestState(value.Get("state").String()),
+		IsDraft:      value.Get("draft").Bool(),
+		Source: preqClient.PullRequestBranch{
+			Name: value.Get("fromRef.displayId").String(),
+			Hash: value.Get("fromRef.latestCommit").String(),
+		},
+		Destination: preqClient.PullRequestBranch{
+			Name: value.Get("toRef.displayId").String(),
+			Hash: value.Get("toRef.latestCommit").String(),
+		},
+		Created: parseTime(value.Get("createdDate")),
+		Updated: parseTime(value.Get("updatedDate")),
+	}
+}
+
+func stateQueryParam(s preqClient.PullRequestState) string {
+	switch s {
+	case preqClient.PullRequestState_OPEN,
+		preqClient.PullRequestState_MERGED,
+		preqClient.PullRequestState_DECLINED:
+		return string(s)
+	}
+
+	return "ALL"
+}
+
+// filterQueryParams maps the filter to the pull request list parameters,
+// the users are filtered by their roles
+func filterQueryParams(f *preqClient.PullRequestFilter) url.Values {
+	query := url.Values{}
+	if f == nil {
+		return query
+	}
+
+	role := 0
+	addRole := func(name, user string) {
+		role++
+		query.Set(fmt.Sprintf("role.%d", role), name)
+		query.Set(fmt.Sprintf("username.%d", role), user)
+	}
+	if f.Author != "" {
+		addRole("AUTHOR", f.Author)
+	}
+	if f.Reviewer != "" {
+		addRole("REVIEWER", f.Reviewer)
+	}
+
+	if f.Destination != "" {
+		query.Set("at", fmt.Sprintf("refs/heads/%s", f.Destination))
+		query.Set("direction", "INCOMING")
+	}
+	if f.Search != "" {
+		query.Set("filterText", f.Search)
+	}
+
+	return query
+}
+
+func (c *BitbucketServerClient) GetPullRequests(
+	o *preqClient.GetPullRequestsOptions,
+) (*preqClient.PullRequestList, error) {
+	if o.State == preqClient.PullRequestState_SUPERSEDED {
+		return nil, preqClient.ErrUnsupportedState
+	}
+
+	u := o.Next
+	if u == "" {
+		prURL, err := c.pullRequestURL(o.Repository, "")
+		if err != nil {
+			return nil, err
+		}
+
+		query := filterQueryParams(o.Filter)
+		query.Set("state", stateQueryParam(o.State))
+		query.Set("limit", fmt.Sprint(pageLimit))
+		u = fmt.Sprintf("%s?%s", prURL, query.Encode())
+	}
+
+	r, err := c.get(u)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs preqClient.PullRequestList
+	parsed := gjson.ParseBytes(r.Body())
+	prs.PageLength = uint(parsed.Get("limit").Uint())
+	if prs.PageLength > 0 {
+		prs.Page = uint(parsed.Get("start").Uint())/prs.PageLength + 1
+	}
+	if !parsed.Get("isLastPage").Bool() && parsed.Get("nextPageStart").Exists() {
+		next, err := url.Parse(u)
+		if err != nil {
+			return nil, err
+		}
+
+		q := next.Query()
+		q.Set("start", parsed.Get("nextPageStart").String())
+		next.RawQuery = q.Encode()
+		prs.NextURL = next.String()
+	}
+	parsed.Get("values").ForEach(func(key, value gjson.Result) bool {
+		// The source branch and the update time are not supported by the
+		// API and are filtered here
+		if pr := parsePullRequest(value); o.Filter.Matches(pr) {
+			prs.Values = append(prs.Values, pr)
+		}
+		return true
+	})
+
+	return &prs, nil
+}
+
+func (c *BitbucketServerClient) getPullRequest(
+	repo *preqClient.Repository,
+	id string,
+) (gjson.Result, error) {
+	u, err := c.pullRequestURL(repo, id)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	r, err := c.get(u)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	return gjson.ParseBytes(r.Body()), nil
+}
+
+func (c *BitbucketServerClient) GetPullRequestInfo(
+	o *preqClient.ApproveOptions,
+) (*preqClient.PullRequest, error) {
+	value, err := c.getPullRequest(o.Repository, o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePullRequest(value), nil
+}
+
+func verifyCreatePullRequestOptions(
+	o *preqClient.CreatePullRequestOptions,
+) error {
+	if o.Source == "" {
+		return errors.New("missing source branch")
+	}
+
+	if o.Destination == "" {
+		return errors.New("missing destination branch")
+	}
+
+	return nil
+}
+
+func (c *BitbucketServerClient) CreatePullRequest(
+	o *preqClient.CreatePullRequestOptions,
+) (*preqClient.PullRequest, error) {
+	err := verifyCreatePullRequestOptions(o)
+	if err != nil {
+		return nil, err
+	}
+
+	project, slug, err := splitRepositoryName(o.Repository.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := c.pullRequestURL(o.Repository, "")
+	if err != nil {
+		return nil, err
+	}
+
+	repository := bbsRepository{
+		Slug:    slug,
+		Project: bbsProject{Key: project},
+	}
+
+	r, err := c.send(resty.MethodPost, u, bbsPROptions{
+		Title:       o.Title,
+		Description: o.Description,
+		FromRef: bbsRef{
+			ID:         fmt.Sprintf("refs/heads/%s", o.Source),
+			Repository: repository,
+		},
+		ToRef: bbsRef{
+			ID:         fmt.Sprintf("refs/heads/%s", o.Destination),
+			Repository: repository,
+		},
+		Reviewers: updateReviewers(gjson.Result{}, o.WithoutAuthor(), nil),
+		Draft:     o.Draft,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePullRequest(gjson.ParseBytes(r.Body())), nil
+}
+
+func (c *BitbucketServerClient) SearchUsers(
+	o *preqClient.SearchUsersOptions,
+) ([]*preqClient.User, error) {
+	r, err := c.request().
+		SetQueryParam("filter", o.Query).
+		Get(fmt.Sprintf("%s/rest/api/1.0/users", c.baseURL))
+	if err != nil {
+		return nil, err
+	}
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
+
+	users := []*preqClient.User{}
+	gjson.GetBytes(r.Body(), "values").ForEach(func(key, value gjson.Result) bool {
+		name := value.Get("name").String()
+		users = append(users, &preqClient.User{
+			ID:       name,
+			Username: name,
+			Name:     value.Get("displayName").String(),
+		})
+		return true
+	})
+
+	return users, nil
+}
+
+func updateReviewers(
+	current gjson.Result,
+	add []string,
+	remove []string,
+) []bbsReviewer {
+	reviewers := []bbsReviewer{}
+	names := map[string]bool{}
+	current.ForEach(func(key, value gjson.Result) bool {
+		name := value.Get("user.name").String()
+		for _, v := range remove {
+			if v == name {
+				return true
+			}
+		}
+
+		names[name] = true
+		reviewers = append(reviewers, bbsReviewer{User: bbsUser{Name: name}})
+		return true
+	})
+
+	for _, name := range add {
+		if !names[name] {
+			reviewers = append(reviewers, bbsReviewer{User: bbsUser{Name: name}})
+		}
+	}
+
+	return reviewers
+}
+
+// UpdatePullRequest replaces the pull request's fields as a whole, the
+// unchanged values are taken from the current version
+func (c *BitbucketServerClient) UpdatePullRequest(
+	o *preqClient.UpdatePullRequestOptions,
+) (*preqClient.PullRequest, error) {
+	current, err := c.getPullRequest(o.Repository, o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	options := bbsPRUpdateOptions{
+		Version:     current.Get("version").Int(),
+		Title:       current.Get("title").String(),
+		Description: current.Get("description").String(),
+		ToRef: bbsRef{
+			ID: current.Get("toRef.id").String(),
+			Repository: bbsRepository{
+				Slug: current.Get("toRef.repository.slug").String(),
+				Project: bbsProject{
+					Key: current.Get("toRef.repository.project.key").String(),
+				},
+			},
+		},
+		Reviewers: updateReviewers(
+			current.Get("reviewers"),
+			o.AddReviewers,
+			o.RemoveReviewers,
+		),
+		Draft: o.Draft,
+	}
+	if o.Title != nil {
+		options.Title = *o.Title
+	}
+	if o.Description != nil {
+		options.Description = *o.Description
+	}
+	if o.Destination != nil {
+		options.ToRef.ID = fmt.Sprintf("refs/heads/%s", *o.Destination)
+	}
+
+	u, err := c.pullRequestURL(o.Repository, o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := c.send(resty.MethodPut, u, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePullRequest(gjson.ParseBytes(r.Body())), nil
+}
+
+// postWithVersion sends a request for an action which requires the
+// current version of the pull request to prevent conflicting updates
+func (c *BitbucketServerClient) postWithVersion(
+	repo *preqClient.Repository,
+	id string,
+	action string,
+	body interface{},
+) (*preqClient.PullRequest, error) {
+	pr, err := c.getPullRequest(repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := c.pullRequestURL(repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := c.send(
+		resty.MethodPost,
+		fmt.Sprintf("%s/%s?version=%d", u, action, pr.Get("version").Int()),
+		body,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePullRequest(gjson.ParseBytes(r.Body())), nil
+}
+
+func (c *BitbucketServerClient) DeclinePullRequest(
+	o *preqClient.DeclinePullRequestOptions,
+) (*preqClient.PullRequest, error) {
+	return c.postWithVersion(o.Repository, o.ID, "decline", nil)
+}
+
+func mergeStrategyID(s preqClient.MergeStrategy) string {
+	switch s {
+	case preqClient.MergeStrategy_MERGE_COMMIT:
+		return "no-ff"
+	case preqClient.MergeStrategy_SQUASH:
+		return "squash"
+	case preqClient.MergeStrategy_FAST_FORWARD:
+		return "ff-only"
+	}
+
+	return ""
+}
+
+func (c *BitbucketServerClient) Merge(
+	o *preqClient.MergeOptions,
+) (*preqClient.PullRequest, error) {
+	return c.postWithVersion(o.Repository, o.ID, "merge", bbsMergeOptions{
+		Message:    o.Message,
+		StrategyID: mergeStrategyID(o.Strategy),
+	})
+}
+
+// GetCurrentUser returns the token's owner. Bitbucket Server returns the
+// authenticated username in the X-AUSERNAME header.
+func (c *BitbucketServerClient) GetCurrentUser() (*preqClient.User, error) {
+	c.currentUserMutex.Lock()
+	defer c.currentUserMutex.Unlock()
+
+	if c.currentUser != nil {
+		return c.currentUser, nil
+	}
+
+	r, err := c.get(fmt.Sprintf("%s/rest/api/1.0/application-properties", c.baseURL))
+	if err != nil {
+		return nil, err
+	}
+
+	username := r.Header().Get("X-AUSERNAME")
+	if username == "" {
+		return nil, ErrUnknownCurrentUser
+	}
+
+	r, err = c.get(fmt.Sprintf(
+		"%s/rest/api/1.0/users/%s",
+		c.baseURL,
+		url.PathEscape(username),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	user := gjson.ParseBytes(r.Body())
+	if user.Get("slug").String() == "" {
+		return nil, ErrUnknownCurrentUser
+	}
+
+	c.currentUser = &preqClient.User{
+		ID:       user.Get("name").String(),
+		Username: user.Get("slug").String(),
+		Name:     user.Get("displayName").String(),
+	}
+
+	return c.currentUser, nil
+}
+
+func (c *BitbucketServerClient) getUserSlug() (string, error) {
+	u, err := c.GetCurrentUser()
+	if err != nil {
+		return "", err
+	}
+
+	return u.Username, nil
+}
+
+func (c *BitbucketServerClient) setParticipantStatus(
+	repo *preqClient.Repository,
+	id string,
+	status string,
+) (*preqClient.PullRequest, error) {
+	slug, err := c.getUserSlug()
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := c.pullRequestURL(repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.send(
+		resty.MethodPut,
+		fmt.Sprintf("%s/participants/%s", u, url.PathEscape(slug)),
+		bbsParticipantOptions{Status: status},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &preqClient.PullRequest{ID: id}, nil
+}
+
+func (c *BitbucketServerClient) Approve(
+	o *preqClient.ApproveOptions,
+) (*preqClient.PullRequest, error) {
+	return c.setParticipantStatus(o.Repository, o.ID, "APPROVED")
+}
+
+func (c *BitbucketServerClient) Unapprove(
+	o *preqClient.UnapproveOptions,
+) (*preqClient.PullRequest, error) {
+	return c.setParticipantStatus(o.Repository, o.ID, "UNAPPROVED")
+}
+
+func (c *BitbucketServerClient) FillMiscInfoAsync(
+	repo *preqClient.Repository,
+	pr *preqClient.PullRequest,
+) error {
+	value, err := c.getPullRequest(repo, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	pr.CommentCount = int(value.Get("properties.commentCount").Int())
+	pr.Approvals = []*preqClient.PullRequestApproval{}
+	pr.ChangesRequests = []*preqClient.PullRequestChangesRequest{}
+	value.Get("reviewers").ForEach(func(key, reviewer gjson.Result) bool {
+		user := reviewer.Get("user.name").String()
+		switch reviewer.Get("status").String() {
+		case "APPROVED":
+			pr.Approvals = append(pr.Approvals, &preqClient.PullRequestApproval{
+				User: user,
+			})
+		case "NEEDS_WORK":
+			pr.ChangesRequests = append(pr.ChangesRequests, &preqClient.PullRequestChangesRequest{
+				User: user,
+			})
+		}
+
+		return true
+	})
+
+	return nil
+}
+
+// awaitsReview reports whether the user is a reviewer of the pull request
+// who has not approved it yet
+func awaitsReview(value gjson.Result, user *preqClient.User) bool {
+	for _, reviewer := range value.Get("reviewers").Array() {
+		if user.Matches(reviewer.Get("user.name").String()) {
+			return reviewer.Get("status").String() != "APPROVED"
+		}
+	}
+
+	return false
+}
+
+func (c *BitbucketServerClient) GetReviewRequests(
+	o *preqClient.GetReviewRequestsOptions,
+) ([]*preqClient.PullRequest, error) {
+	user := o.User
+	if user == nil {
+		var err error
+		user, err = c.GetCurrentUser()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	prURL, err := c.pullRequestURL(o.Repository, "")
+	if err != nil {
+		return nil, err
+	}
+
+	query := filterQueryParams(&preqClient.PullRequestFilter{Reviewer: user.ID})
+	query.Set("state", preqClient.PullRequestState_OPEN)
+
+	values := []*preqClient.PullRequest{}
+	err = c.getAll(
+		fmt.Sprintf("%s?%s", prURL, query.Encode()),
+		func(value gjson.Result) {
+			if awaitsReview(value, user) {
+				values = append(values, parsePullRequest(value))
+			}
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// parseComment returns the comment and its replies, replies are nested
+// in the `comments` field of their parent
+func parseComment(
+	value gjson.Result,
+	anchor gjson.Result,
+	parentID string,
+) []*preqClient.PullRequestComment {
+	var typ preqClient.CommentType = preqClient.CommentTypeGlobal
+	if parentID != "" {
+		typ = preqClient.CommentTypeReply
+	} else if anchor.Exists() {
+		typ = preqClient.CommentTypeInline
+		if !anchor.Get("line").Exists() {
+			typ = preqClient.CommentTypeFile
+		}
+	}
+
+	var beforeLineNumber, afterLineNumber uint
+	if anchor.Get("fileType").String() == "FROM" {
+		beforeLineNumber = uint(anchor.Get("line").Uint())
+	} else {
+		afterLineNumber = uint(anchor.Get("line").Uint())
+	}
+
+	comment := &preqClient.PullRequestComment{
+		ID:               value.Get("id").String(),
+		Type:             typ,
+		ParentID:         parentID,
+		Content:          value.Get("text").String(),
+		Created:          parseTime(value.Get("createdDate")),
+		Updated:          parseTime(value.Get("updatedDate")),
+		User:             value.Get("author.name").String(),
+		BeforeLineNumber: beforeLineNumber,
+		AfterLineNumber:  afterLineNumber,
+		FilePath:         anchor.Get("path").String(),
+		CommitHash:       anchor.Get("toHash").String(),
+		IsBeingStored:    false,
+		IsBeingDeleted:   false,
+	}
+
+	comments := []*preqClient.PullRequestComment{comment}
+	value.Get("comments").ForEach(func(key, reply gjson.Result) bool {
+		comments = append(comments, parseComment(reply, anchor, comment.ID)...)
+		return true
+	})
+
+	return comments
+}
+
+// normalizeDiffPrefixes replaces the src:// and dst:// prefixes of the paths
+// in the file headers of the raw diff with the a/ and b/ of git diff
+func normalizeDiffPrefixes(diff []byte) []byte {
+	lines := strings.SplitAfter(string(diff), "\n")
+	header := false
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			header = true
+		case strings.HasPrefix(line, "@@"):
+			header = false
+		}
+
+		if header {
+			line = strings.ReplaceAll(line, " src://", " a/")
+			lines[i] = strings.ReplaceAll(line, " dst://", " b/")
+		}
+	}
+
+	return []byte(strings.Join(lines, ""))
+}
+
+func (c *BitbucketServerClient) GetDiff(o *preqClient.GetDiffOptions) ([]byte, error) {
+	u, err := c.pullRequestURL(o.Repository, o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := c.get(u + ".diff")
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizeDiffPrefixes(r.Body()), nil
+}
+
+func (c *BitbucketServerClient) GetComments(
+	o *preqClient.GetCommentsOptions,
+) ([]*preqClient.PullRequestComment, error) {
+	u, err := c.pullRequestURL(o.Repository, o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := []*preqClient.PullRequestComment{}
+	seen := map[string]*preqClient.PullRequestComment{}
+	err = c.getAll(fmt.Sprintf("%s/activities", u), func(value gjson.Result) {
+		if value.Get("action").String() != "COMMENTED" {
+			return
+		}
+
+		for _, comment := range parseComment(
+			value.Get("comment"),
+			value.Get("commentAnchor"),
+			"",
+		) {
+			// Replies can also be listed as activities of their own,
+			// in which case they are missing the parent reference
+			if existing, ok := seen[comment.ID]; ok {
+				if existing.ParentID == "" && comment.ParentID != "" {
+					*existing = *comment
+				}
+				continue
+			}
+
+			seen[comment.ID] = comment
+			comments = append(comments, comment)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// anchorLineType returns the line type and the file type of the comment's
+// anchor, the unchanged lines are anchored to the new version of the file
+func anchorLineType(ref *preqClient.CreateCommentOptionsLineRef) (string, string) {
+	switch {
+	case ref.Context:
+		return "CONTEXT", "TO"
+	case ref.Type == preqClient.OriginalLineNumber:
+		return "REMOVED", "FROM"
+	default:
+		return "ADDED", "TO"
+	}
+}
+
+func (c *BitbucketServerClient) CreateComment(
+	o *preqClient.CreateCommentOptions,
+) (*preqClient.PullRequestComment, error) {
+	u, err := c.pullRequestURL(o.Repository, o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	options := bbsCommentOptions{Text: o.Content}
+	if o.ParentRef != nil {
+		id := gjson.Parse(o.ParentRef.ID).Int()
+		options.Parent = &bbsCommentParent{ID: id}
+	} else if o.FilePath != "" {
+		options.Anchor = &bbsCommentAnchor{
+			Path:     o.FilePath,
+			DiffType: "EFFECTIVE",
+		}
+
+		if o.LineRef != nil {
+			options.Anchor.Line = o.LineRef.LineNumber
+			options.Anchor.LineType, options.Anchor.FileType = anchorLineType(o.LineRef)
+		}
+	}
+
+	r, err := c.send(resty.MethodPost, fmt.Sprintf("%s/comments", u), options)
+	if err != nil {
+		return nil, err
+	}
+
+	parentID := ""
+	if o.ParentRef != nil {
+		parentID = o.ParentRef.ID
+	}
+
+	parsed := gjson.ParseBytes(r.Body())
+	return parseComment(parsed, parsed.Get("anchor"), parentID)[0], nil
+}
+
+func (c *BitbucketServerClient) DeleteComment(
+	o *preqClient.DeleteCommentOptions,
+) error {
+	u, err := c.pullRequestURL(o.Repository, o.ID)
+	if err != nil {
+		return err
+	}
+
+	// Deleting requires the current version of the comment
+	commentURL := fmt.Sprintf("%s/comments/%s", u, o.CommentID)
+	r, err := c.get(commentURL)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.send(
+		resty.MethodDelete,
+		fmt.Sprintf("%s?version=%d", commentURL, gjson.GetBytes(r.Body(), "version").Int()),
+		nil,
+	)
+
+	return err
+}
@@ -0,0 +1,140 @@
+package bitbucketserver
+
+import (
+	preqClient "preq/internal/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func Test_splitRepositoryName(t *testing.T) {
+	t.Run("splits the project key and the repository slug", func(t *testing.T) {
+		project, slug, err := splitRepositoryName("PROJ/repo")
+		assert.NoError(t, err)
+		assert.Equal(t, "PROJ", project)
+		assert.Equal(t, "repo", slug)
+	})
+
+	t.Run("fails on nested names", func(t *testing.T) {
+		_, _, err := splitRepositoryName("scm/PROJ/repo")
+		assert.ErrorIs(t, err, ErrInvalidRepositoryName)
+	})
+}
+
+func Test_parseComment(t *testing.T) {
+	t.Run("flattens nested replies", func(t *testing.T) {
+		v := parseComment(gjson.Parse(`{
+			"id": 1,
+			"text": "first",
+			"author": {"name": "a"},
+			"comments": [{
+				"id": 2,
+				"text": "reply",
+				"comments": [{"id": 3, "text": "nested reply"}]
+			}]
+		}`), gjson.Result{}, "")
+		assert.Equal(t, 3, len(v))
+		assert.Equal(t, preqClient.CommentType(preqClient.CommentTypeGlobal), v[0].Type)
+		assert.Equal(t, preqClient.CommentType(preqClient.CommentTypeReply), v[1].Type)
+		assert.Equal(t, "1", v[1].ParentID)
+		assert.Equal(t, "2", v[2].ParentID)
+	})
+
+	t.Run("places comments on the original file by the before line", func(t *testing.T) {
+		anchor := gjson.Parse(`{"path": "a.go", "line": 4, "fileType": "FROM", "toHash": "deadbeef"}`)
+		v := parseComment(gjson.Parse(`{"id": 1}`), anchor, "")
+		assert.Equal(t, preqClient.CommentType(preqClient.CommentTypeInline), v[0].Type)
+		assert.Equal(t, uint(4), v[0].BeforeLineNumber)
+		assert.Equal(t, uint(0), v[0].AfterLineNumber)
+		assert.Equal(t, "a.go", v[0].FilePath)
+		assert.Equal(t, "deadbeef", v[0].CommitHash)
+	})
+
+	t.Run("anchors without a line are file comments", func(t *testing.T) {
+		v := parseComment(gjson.Parse(`{"id": 1}`), gjson.Parse(`{"path": "a.go"}`), "")
+		assert.Equal(t, preqClient.CommentType(preqClient.CommentTypeFile), v[0].Type)
+	})
+}
+
+func Test_updateReviewers(t *testing.T) {
+	t.Run("adds and removes reviewers by name", func(t *testing.T) {
+		v := updateReviewers(
+			gjson.Parse(`[{"user": {"name": "a"}}, {"user": {"name": "b"}}]`),
+			[]string{"c", "a"},
+			[]string{"b"},
+		)
+		assert.Equal(t, []bbsReviewer{
+			{User: bbsUser{Name: "a"}},
+			{User: bbsUser{Name: "c"}},
+		}, v)
+	})
+}
+
+func Test_filterQueryParams(t *testing.T) {
+	assert.Empty(t, filterQueryParams(nil))
+	assert.Equal(
+		t,
+		"at=refs%2Fheads%2Fmain&direction=INCOMING&filterText=parser&role.1=REVIEWER&username.1=bob",
+		filterQueryParams(&preqClient.PullRequestFilter{
+			Reviewer:    "bob",
+			Destination: "main",
+			Search:      "parser",
+		}).Encode(),
+	)
+}
+
+func Test_awaitsReview(t *testing.T) {
+	user := &preqClient.User{ID: "me", Username: "me"}
+
+	value := gjson.Parse(`{"reviewers": [
+		{"user": {"name": "other"}, "status": "APPROVED"},
+		{"user": {"name": "me"}, "status": "UNAPPROVED"}
+	]}`)
+	assert.True(t, awaitsReview(value, user))
+
+	value = gjson.Parse(`{"reviewers": [{"user": {"name": "me"}, "status": "APPROVED"}]}`)
+	assert.False(t, awaitsReview(value, user))
+
+	value = gjson.Parse(`{"reviewers": [{"user": {"name": "other"}, "status": "UNAPPROVED"}]}`)
+	assert.False(t, awaitsReview(value, user))
+}
+
+func Test_normalizeDiffPrefixes(t *testing.T) {
+	diff := "diff --git src://a.txt dst://a.txt\n" +
+		"index 1..2 100644\n" +
+		"--- src://a.txt\n" +
+		"+++ dst://a.txt\n" +
+		"@@ -1 +1 @@\n" +
+		"-- src://kept\n" +
+		"+changed\n"
+
+	assert.Equal(t, "diff --git a/a.txt b/a.txt\n"+
+		"index 1..2 100644\n"+
+		"--- a/a.txt\n"+
+		"+++ b/a.txt\n"+
+		"@@ -1 +1 @@\n"+
+		"-- src://kept\n"+
+		"+changed\n", string(normalizeDiffPrefixes([]byte(diff))))
+}
+
+func Test_anchorLineType(t *testing.T) {
+	lineType, fileType := anchorLineType(&preqClient.CreateCommentOptionsLineRef{
+		Type: preqClient.OriginalLineNumber,
+	})
+	assert.Equal(t, "REMOVED", lineType)
+	assert.Equal(t, "FROM", fileType)
+
+	lineType, fileType = anchorLineType(&preqClient.CreateCommentOptionsLineRef{
+		Type: preqClient.NewLineNumber,
+	})
+	assert.Equal(t, "ADDED", lineType)
+	assert.Equal(t, "TO", fileType)
+
+	lineType, fileType = anchorLineType(&preqClient.CreateCommentOptionsLineRef{
+		Type:    preqClient.NewLineNumber,
+		Context: true,
+	})
+	assert.Equal(t, "CONTEXT", lineType)
+	assert.Equal(t, "TO", fileType)
+}
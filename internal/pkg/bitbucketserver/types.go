@@ -0,0 +1,74 @@
+package bitbucketserver
+
+type bbsError struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type bbsProject struct {
+	Key string `json:"key"`
+}
+
+type bbsRepository struct {
+	Slug    string     `json:"slug"`
+	Project bbsProject `json:"project"`
+}
+
+type bbsRef struct {
+	ID         string        `json:"id"`
+	Repository bbsRepository `json:"repository"`
+}
+
+type bbsUser struct {
+	Name string `json:"name"`
+}
+
+type bbsReviewer struct {
+	User bbsUser `json:"user"`
+}
+
+type bbsPROptions struct {
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	FromRef     bbsRef        `json:"fromRef"`
+	ToRef       bbsRef        `json:"toRef"`
+	Reviewers   []bbsReviewer `json:"reviewers,omitempty"`
+	Draft       bool          `json:"draft,omitempty"`
+}
+
+type bbsPRUpdateOptions struct {
+	Version     int64         `json:"version"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	ToRef       bbsRef        `json:"toRef"`
+	Reviewers   []bbsReviewer `json:"reviewers"`
+	Draft       *bool         `json:"draft,omitempty"`
+}
+
+type bbsMergeOptions struct {
+	Message    string `json:"message,omitempty"`
+	StrategyID string `json:"strategyId,omitempty"`
+}
+
+type bbsParticipantOptions struct {
+	Status string `json:"status"`
+}
+
+type bbsCommentParent struct {
+	ID int64 `json:"id"`
+}
+
+type bbsCommentAnchor struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line,omitempty"`
+	LineType string `json:"lineType,omitempty"`
+	FileType string `json:"fileType,omitempty"`
+	DiffType string `json:"diffType,omitempty"`
+}
+
+type bbsCommentOptions struct {
+	Text   string            `json:"text"`
+	Parent *bbsCommentParent `json:"parent,omitempty"`
+	Anchor *bbsCommentAnchor `json:"anchor,omitempty"`
+}
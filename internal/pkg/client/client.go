@@ -9,10 +9,11 @@ import (
 
 var (
 	ErrUnknownRepositoryProvider = errors.New(strings.TrimSpace(`
-		unknown repository provider, expected (bitbucket)
+		unknown repository provider, expected (bitbucket, bitbucketserver, github, gitlab)
 	`))
 	ErrMissingBitbucketUsername = errors.New("bitbucket username is missing")
 	ErrMissingBitbucketPassword = errors.New("bitbucket password is missing")
+	ErrUnsupportedState         = errors.New("the pull request state is not supported by the provider")
 )
 
 type Client interface {
@@ -27,6 +28,11 @@ type Client interface {
 	GetComments(o *GetCommentsOptions) ([]*PullRequestComment, error)
 	CreateComment(o *CreateCommentOptions) (*PullRequestComment, error)
 	DeleteComment(o *DeleteCommentOptions) error
+	UpdatePullRequest(o *UpdatePullRequestOptions) (*PullRequest, error)
+	SearchUsers(o *SearchUsersOptions) ([]*User, error)
+	GetCurrentUser() (*User, error)
+	GetReviewRequests(o *GetReviewRequestsOptions) ([]*PullRequest, error)
+	GetDiff(o *GetDiffOptions) ([]byte, error)
 }
 
 type RepositoryProvider string
@@ -44,13 +50,29 @@ func (rp RepositoryProvider) IsValid() bool {
 }
 
 type list struct {
-	BITBUCKET RepositoryProvider
-	GITHUB    RepositoryProvider
+	BITBUCKET        RepositoryProvider
+	BITBUCKET_SERVER RepositoryProvider
+	GITHUB           RepositoryProvider
+	GITLAB           RepositoryProvider
 }
 
 var RepositoryProviderEnum = &list{
-	BITBUCKET: RepositoryProvider("bitbucket"),
-	GITHUB:    RepositoryProvider("github"),
+	BITBUCKET:        RepositoryProvider("bitbucket"),
+	BITBUCKET_SERVER: RepositoryProvider("bitbucketserver"),
+	GITHUB:           RepositoryProvider("github"),
+	GITLAB:           RepositoryProvider("gitlab"),
+}
+
+// RepositoryProviders returns all supported repository providers
+func RepositoryProviders() []RepositoryProvider {
+	v := reflect.ValueOf(*RepositoryProviderEnum)
+
+	providers := make([]RepositoryProvider, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		providers = append(providers, v.Field(i).Interface().(RepositoryProvider))
+	}
+
+	return providers
 }
 
 func ParseRepositoryProvider(
@@ -62,13 +84,14 @@ func ParseRepositoryProvider(
 		return RepositoryProviderEnum.BITBUCKET, nil
 	case "github.com", "github":
 		return RepositoryProviderEnum.GITHUB, nil
+	case "gitlab.com", "gitlab":
+		return RepositoryProviderEnum.GITLAB, nil
+	case "bitbucketserver":
+		// Bitbucket Server is always self-hosted, its hosts are
+		// resolved through the aliases
+		return RepositoryProviderEnum.BITBUCKET_SERVER, nil
 	default:
-		providers := []RepositoryProvider{
-			RepositoryProviderEnum.BITBUCKET,
-			RepositoryProviderEnum.GITHUB,
-		}
-
-		for _, p := range providers {
+		for _, p := range RepositoryProviders() {
 			for _, alias := range aliases[p] {
 				if alias == s {
 					return p, nil
@@ -118,8 +141,67 @@ const (
 
 type GetPullRequestsOptions struct {
 	Repository *Repository
-	State      PullRequestState
-	Next       string
+	// State of the listed pull requests, all states are listed when empty
+	State  PullRequestState
+	Next   string
+	Filter *PullRequestFilter
+}
+
+// GetReviewRequestsOptions lists the open pull requests of the repository
+// which wait for the review of the user
+type GetReviewRequestsOptions struct {
+	Repository *Repository
+	// User is the reviewer, the current user when nil
+	User *User
+}
+
+// PullRequestFilter narrows down the listed pull requests. The providers
+// pass the filters to their APIs where possible and match the rest with
+// Matches.
+type PullRequestFilter struct {
+	// Author and Reviewer are the user IDs or usernames
+	Author       string
+	Reviewer     string
+	Source       string
+	Destination  string
+	UpdatedSince time.Time
+	// Search matches the title or the description
+	Search string
+}
+
+// Matches reports whether the pull request matches the branch, update time
+// and search filters, the users are only filtered by the providers
+func (f *PullRequestFilter) Matches(pr *PullRequest) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.Source != "" && pr.Source.Name != f.Source {
+		return false
+	}
+
+	if f.Destination != "" && pr.Destination.Name != f.Destination {
+		return false
+	}
+
+	if !f.UpdatedSince.IsZero() && pr.Updated.Before(f.UpdatedSince) {
+		return false
+	}
+
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		return strings.Contains(strings.ToLower(pr.Title), search) ||
+			strings.Contains(strings.ToLower(pr.Description), search)
+	}
+
+	return true
+}
+
+// GetDiffOptions requests the changes of the pull request as a unified diff
+// in the format of git diff
+type GetDiffOptions struct {
+	Repository *Repository
+	ID         string
 }
 
 type GetCommentsOptions struct {
@@ -137,6 +219,11 @@ const (
 type CreateCommentOptionsLineRef struct {
 	LineNumber int
 	Type       CommentLineNumberType
+	// Context marks the unchanged lines of the diff, their LineNumber is the
+	// number in the new version of the file and OriginalLineNumber the one
+	// in the original version
+	Context            bool
+	OriginalLineNumber int
 }
 
 type CreateCommentOptionsParentRef struct {
@@ -163,9 +250,57 @@ type DeclinePullRequestOptions struct {
 	ID         string
 }
 
+type MergeStrategy string
+
+const (
+	// MergeStrategy_DEFAULT leaves the choice to the repository settings
+	MergeStrategy_DEFAULT      MergeStrategy = ""
+	MergeStrategy_MERGE_COMMIT MergeStrategy = "merge_commit"
+	MergeStrategy_SQUASH       MergeStrategy = "squash"
+	// MergeStrategy_FAST_FORWARD rebases the source branch onto
+	// the destination on providers without fast-forward merges
+	MergeStrategy_FAST_FORWARD MergeStrategy = "fast_forward"
+)
+
+var ErrUnknownMergeStrategy = errors.New(strings.TrimSpace(`
+	unknown merge strategy, expected (merge_commit, squash, fast_forward)
+`))
+
+func ParseMergeStrategy(s string) (MergeStrategy, error) {
+	switch s {
+	case "":
+		return MergeStrategy_DEFAULT, nil
+	case "merge_commit", "merge":
+		return MergeStrategy_MERGE_COMMIT, nil
+	case "squash":
+		return MergeStrategy_SQUASH, nil
+	case "fast_forward", "ff", "rebase":
+		return MergeStrategy_FAST_FORWARD, nil
+	}
+
+	return "", ErrUnknownMergeStrategy
+}
+
 type MergeOptions struct {
 	Repository *Repository
 	ID         string
+	Strategy   MergeStrategy
+	// Message is the optional commit message of the merge commit
+	Message string
+}
+
+type UpdatePullRequestOptions struct {
+	Repository *Repository
+	ID         string
+	// Fields left nil are not changed
+	Title       *string
+	Description *string
+	Destination *string
+	Draft       *bool
+	// Reviewers are identified by the provider's user identifier,
+	// e.g. the username on GitHub and GitLab or the UUID on Bitbucket
+	AddReviewers    []string
+	RemoveReviewers []string
 }
 
 type ApproveOptions struct {
@@ -181,10 +316,17 @@ type UnapproveOptions struct {
 type CreatePullRequestOptions struct {
 	Repository  *Repository
 	Title       string
+	Description string
 	Source      string
 	Destination string
 	CloseBranch bool
 	Draft       bool
+	// Reviewers are identified by the provider's user identifier, see User.ID
+	Reviewers            []string
+	SkipDefaultReviewers bool
+	// Author is the identifier of the current user, which is left out of
+	// the reviewers
+	Author string
 }
 
 type PullRequestApproval struct {
@@ -229,6 +371,10 @@ func (prc PullRequestComment) IsOutdated(sourceHash string) bool {
 type PullRequestBranch struct {
 	Name string
 	Hash string
+	// Repository is the full name of the branch's repository, which
+	// differs from the pull request's repository for forks. Empty when the
+	// provider does not report it.
+	Repository string
 }
 
 type PullRequest struct {
@@ -239,6 +385,7 @@ type PullRequest struct {
 	User            string
 	URL             string
 	State           PullRequestState
+	IsDraft         bool
 	Source          PullRequestBranch
 	Destination     PullRequestBranch
 	Created         time.Time
@@ -249,7 +396,45 @@ type PullRequest struct {
 }
 
 type User struct {
-	ID string
+	// ID is the identifier used by the provider's API, e.g. the UUID on
+	// Bitbucket or the login on GitHub
+	ID       string
+	Username string
+	Name     string
+}
+
+// Matches reports whether the name refers to the user, providers show
+// either of the identifiers depending on the endpoint
+func (u *User) Matches(name string) bool {
+	if u == nil || name == "" {
+		return false
+	}
+
+	for _, v := range []string{u.ID, u.Username, u.Name} {
+		if v != "" && strings.EqualFold(v, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithoutAuthor returns the reviewers without the author, the providers
+// refuse the pull request's author as a reviewer
+func (o *CreatePullRequestOptions) WithoutAuthor() []string {
+	reviewers := []string{}
+	for _, r := range o.Reviewers {
+		if o.Author == "" || !strings.EqualFold(r, o.Author) {
+			reviewers = append(reviewers, r)
+		}
+	}
+
+	return reviewers
+}
+
+type SearchUsersOptions struct {
+	Repository *Repository
+	Query      string
 }
 
 // type Reviewer struct {
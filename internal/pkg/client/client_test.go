@@ -0,0 +1,99 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMergeStrategy(t *testing.T) {
+	t.Run("returns the default strategy for empty input", func(t *testing.T) {
+		s, err := ParseMergeStrategy("")
+		assert.NoError(t, err)
+		assert.Equal(t, MergeStrategy_DEFAULT, s)
+	})
+
+	t.Run("accepts aliases", func(t *testing.T) {
+		s, err := ParseMergeStrategy("rebase")
+		assert.NoError(t, err)
+		assert.Equal(t, MergeStrategy_FAST_FORWARD, s)
+	})
+
+	t.Run("fails on unknown strategies", func(t *testing.T) {
+		_, err := ParseMergeStrategy("octopus")
+		assert.EqualError(t, err, ErrUnknownMergeStrategy.Error())
+	})
+}
+
+func TestParseRepositoryProvider(t *testing.T) {
+	t.Run("recognizes the public hosts", func(t *testing.T) {
+		p, err := ParseRepositoryProvider("gitlab.com", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, RepositoryProviderEnum.GITLAB, p)
+	})
+
+	t.Run("recognizes aliases", func(t *testing.T) {
+		p, err := ParseRepositoryProvider("git.corp", map[RepositoryProvider][]string{
+			RepositoryProviderEnum.GITLAB: {"git.corp"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, RepositoryProviderEnum.GITLAB, p)
+	})
+
+	t.Run("fails on unknown hosts", func(t *testing.T) {
+		_, err := ParseRepositoryProvider("git.corp", nil)
+		assert.ErrorIs(t, err, ErrUnknownRepositoryProvider)
+	})
+}
+
+func TestUser_Matches(t *testing.T) {
+	u := &User{ID: "{uuid}", Username: "jdoe", Name: "John Doe"}
+
+	t.Run("matches any of the identifiers", func(t *testing.T) {
+		assert.True(t, u.Matches("{uuid}"))
+		assert.True(t, u.Matches("JDoe"))
+		assert.True(t, u.Matches("John Doe"))
+	})
+
+	t.Run("does not match other users", func(t *testing.T) {
+		assert.False(t, u.Matches("jane"))
+		assert.False(t, u.Matches(""))
+	})
+
+	t.Run("does not match when the user is unknown", func(t *testing.T) {
+		var unknown *User
+		assert.False(t, unknown.Matches("jdoe"))
+	})
+}
+
+func TestCreatePullRequestOptions_WithoutAuthor(t *testing.T) {
+	t.Run("removes the author from the reviewers", func(t *testing.T) {
+		o := &CreatePullRequestOptions{
+			Reviewers: []string{"alice", "JDoe", "bob"},
+			Author:    "jdoe",
+		}
+		assert.Equal(t, []string{"alice", "bob"}, o.WithoutAuthor())
+	})
+}
+
+func TestPullRequestFilter_Matches(t *testing.T) {
+	pr := &PullRequest{
+		Title:       "Fix the parser",
+		Description: "Handles empty input",
+		Source:      PullRequestBranch{Name: "fix"},
+		Destination: PullRequestBranch{Name: "main"},
+		Updated:     time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	var nilFilter *PullRequestFilter
+	assert.True(t, nilFilter.Matches(pr))
+	assert.True(t, (&PullRequestFilter{Source: "fix", Destination: "main"}).Matches(pr))
+	assert.False(t, (&PullRequestFilter{Source: "main"}).Matches(pr))
+	assert.False(t, (&PullRequestFilter{Destination: "develop"}).Matches(pr))
+	assert.True(t, (&PullRequestFilter{UpdatedSince: pr.Updated}).Matches(pr))
+	assert.False(t, (&PullRequestFilter{UpdatedSince: pr.Updated.Add(time.Hour)}).Matches(pr))
+	assert.True(t, (&PullRequestFilter{Search: "PARSER"}).Matches(pr))
+	assert.True(t, (&PullRequestFilter{Search: "empty"}).Matches(pr))
+	assert.False(t, (&PullRequestFilter{Search: "lexer"}).Matches(pr))
+}
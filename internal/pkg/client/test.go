@@ -1,13 +1,27 @@
 package client
 
 type MockClient struct {
-	ErrorValue error
+	ErrorValue       error
+	UsersValue       []*User
+	CurrentUserValue *User
+	// PullRequestPages are the pages returned by GetPullRequests, keyed by
+	// the next URL, the first page by an empty key
+	PullRequestPages map[string]*PullRequestList
+	// ReviewRequestsValue are the pull requests returned by
+	// GetReviewRequests
+	ReviewRequestsValue []*PullRequest
+	// DiffValue is the diff returned by GetDiff
+	DiffValue []byte
 }
 
 func (c *MockClient) GetPullRequests(
 	o *GetPullRequestsOptions,
 ) (*PullRequestList, error) {
-	return nil, c.ErrorValue
+	if c.ErrorValue != nil {
+		return nil, c.ErrorValue
+	}
+
+	return c.PullRequestPages[o.Next], nil
 }
 
 func (c *MockClient) CreatePullRequest(
@@ -22,6 +36,14 @@ func (c *MockClient) ApprovePullRequest(
 	return nil, c.ErrorValue
 }
 
+func (c *MockClient) Approve(o *ApproveOptions) (*PullRequest, error) {
+	return nil, c.ErrorValue
+}
+
+func (c *MockClient) Unapprove(o *UnapproveOptions) (*PullRequest, error) {
+	return nil, c.ErrorValue
+}
+
 func (c *MockClient) DeclinePullRequest(
 	o *DeclinePullRequestOptions,
 ) (*PullRequest, error) {
@@ -37,3 +59,49 @@ func (c *MockClient) GetPullRequestInfo(
 func (c *MockClient) Merge(o *MergeOptions) (*PullRequest, error) {
 	return nil, c.ErrorValue
 }
+
+func (c *MockClient) FillMiscInfoAsync(repo *Repository, pr *PullRequest) error {
+	return c.ErrorValue
+}
+
+func (c *MockClient) GetComments(
+	o *GetCommentsOptions,
+) ([]*PullRequestComment, error) {
+	return nil, c.ErrorValue
+}
+
+func (c *MockClient) CreateComment(
+	o *CreateCommentOptions,
+) (*PullRequestComment, error) {
+	return nil, c.ErrorValue
+}
+
+func (c *MockClient) DeleteComment(o *DeleteCommentOptions) error {
+	return c.ErrorValue
+}
+
+func (c *MockClient) UpdatePullRequest(
+	o *UpdatePullRequestOptions,
+) (*PullRequest, error) {
+	if c.ErrorValue != nil {
+		return nil, c.ErrorValue
+	}
+
+	return &PullRequest{ID: o.ID}, nil
+}
+
+func (c *MockClient) SearchUsers(o *SearchUsersOptions) ([]*User, error) {
+	return c.UsersValue, c.ErrorValue
+}
+
+func (c *MockClient) GetCurrentUser() (*User, error) {
+	return c.CurrentUserValue, c.ErrorValue
+}
+
+func (c *MockClient) GetReviewRequests(o *GetReviewRequestsOptions) ([]*PullRequest, error) {
+	return c.ReviewRequestsValue, c.ErrorValue
+}
+
+func (c *MockClient) GetDiff(o *GetDiffOptions) ([]byte, error) {
+	return c.DiffValue, c.ErrorValue
+}
@@ -3,16 +3,20 @@ package github
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/go-resty/resty/v2"
 )
 
 type newClientOptions struct {
-	Token string
+	Token   string
+	BaseURL string
 }
 
 type service struct {
-	token string
+	token   string
+	baseURL string
 }
 
 type SearchService service
@@ -26,10 +30,12 @@ type client struct {
 func newClient(o *newClientOptions) *client {
 	return &client{
 		Search: &SearchService{
-			token: o.Token,
+			token:   o.Token,
+			baseURL: o.BaseURL,
 		},
 		User: &UserService{
-			token: o.Token,
+			token:   o.Token,
+			baseURL: o.BaseURL,
 		},
 	}
 }
@@ -87,7 +93,7 @@ func (c *UserService) Current(ctx context.Context) (*User, error) {
 	r, err := resty.New().R().
 		SetAuthToken(c.token).
 		SetError(githubError{}).
-		Get("https://api.github.com/user")
+		Get(fmt.Sprintf("%s/user", c.baseURL))
 	if err != nil {
 		return nil, err
 	}
@@ -105,6 +111,7 @@ type IssuesSearchResult struct {
 	TotalCount        int     `json:"total_count"`
 	IncompleteResults bool    `json:"incomplete_results"`
 	Items             []*Item `json:"items"`
+	NextURL           string  `json:"-"`
 }
 
 type Item struct {
@@ -145,20 +152,41 @@ type Item struct {
 // }
 
 func (c *SearchService) Issues(ctx context.Context, query string) (*IssuesSearchResult, error) {
-	r, err := resty.New().R().
+	return c.issues(
+		ctx,
+		resty.New().R().SetQueryParam("q", query),
+		fmt.Sprintf("%s/search/issues", c.baseURL),
+	)
+}
+
+// IssuesNext fetches the next page of a search result
+func (c *SearchService) IssuesNext(ctx context.Context, nextURL string) (*IssuesSearchResult, error) {
+	return c.issues(ctx, resty.New().R(), nextURL)
+}
+
+func (c *SearchService) issues(
+	ctx context.Context,
+	request *resty.Request,
+	url string,
+) (*IssuesSearchResult, error) {
+	r, err := request.
+		SetContext(ctx).
 		SetAuthToken(c.token).
 		SetError(githubError{}).
-		SetQueryParam("q", query).
-		Get("https://api.github.com/search/issues")
+		Get(url)
 	if err != nil {
 		return nil, err
 	}
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
 
 	var isr *IssuesSearchResult
 	err = json.Unmarshal(r.Body(), &isr)
 	if err != nil {
 		return nil, err
 	}
+	isr.NextURL = getNextPageURL(r.Header().Get("Link"))
 
-	return isr, err
+	return isr, nil
 }
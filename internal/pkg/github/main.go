@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	preqClient "preq/internal/pkg/client"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/rs/zerolog/log"
@@ -22,27 +25,40 @@ var (
 	ErrMissingGithubPassword = errors.New("github password is missing")
 )
 
+const DefaultBaseURL = "https://api.github.com"
+
 type GithubCloudClient struct {
 	username string
 	token    string
+	baseURL  string
 }
 
 type ClientOptions struct {
 	Username string
 	Password string
 	Token    string
+	// BaseURL is the API URL, e.g. https://github.example.com/api/v3
+	// for GitHub Enterprise. Defaults to DefaultBaseURL.
+	BaseURL string
 }
 
 func New(o *ClientOptions) preqClient.Client {
+	baseURL := strings.TrimSuffix(o.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
 	return &GithubCloudClient{
 		username: o.Username,
 		token:    o.Token,
+		baseURL:  baseURL,
 	}
 }
 
 type clientConfiguration struct {
 	username string
 	token    string
+	baseURL  string
 }
 
 func getDefaultConfiguration() (*clientConfiguration, error) {
@@ -58,6 +74,7 @@ func getDefaultConfiguration() (*clientConfiguration, error) {
 	return &clientConfiguration{
 		username: username,
 		token:    token,
+		baseURL:  viper.GetString("github.baseUrl"),
 	}, nil
 }
 
@@ -67,10 +84,11 @@ func DefaultClient() (preqClient.Client, error) {
 		return nil, err
 	}
 
-	return &GithubCloudClient{
-		username: config.username,
-		token:    config.token,
-	}, nil
+	return New(&ClientOptions{
+		Username: config.username,
+		Token:    config.token,
+		BaseURL:  config.baseURL,
+	}), nil
 }
 
 type ghPRSourceBranchOptions struct {
@@ -92,7 +110,7 @@ type ghPROptions struct {
 	Base                string `json:"base,omitempty"`
 	Body                string `json:"body,omitempty"`
 	State               string `json:"state,omitempty"`
-	MaintainerCanModify bool   `json:"maintainer_can_modify,omitempty`
+	MaintainerCanModify bool   `json:"maintainer_can_modify,omitempty"`
 	Draft               bool   `json:"draft,omitempty"`
 }
 
@@ -109,43 +127,542 @@ type bbError struct {
 // 	Values     []*client.PullRequest `json:"values"`
 // }
 
+type ghCommentOptions struct {
+	Body        string `json:"body"`
+	CommitID    string `json:"commit_id,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Side        string `json:"side,omitempty"`
+	SubjectType string `json:"subject_type,omitempty"`
+}
+
+func parseIssueComment(value gjson.Result) *preqClient.PullRequestComment {
+	return &preqClient.PullRequestComment{
+		ID:             value.Get("id").String(),
+		Type:           preqClient.CommentTypeGlobal,
+		Content:        value.Get("body").String(),
+		Created:        value.Get("created_at").Time(),
+		Updated:        value.Get("updated_at").Time(),
+		User:           value.Get("user.login").String(),
+		IsBeingStored:  false,
+		IsBeingDeleted: false,
+	}
+}
+
+func parseReviewComment(value gjson.Result) *preqClient.PullRequestComment {
+	var typ preqClient.CommentType = preqClient.CommentTypeInline
+	if value.Get("in_reply_to_id").Exists() {
+		typ = preqClient.CommentTypeReply
+	} else if value.Get("subject_type").String() == "file" {
+		typ = preqClient.CommentTypeFile
+	}
+
+	// Comments which do not apply to the latest commit anymore have their
+	// `line` set to null and only keep the original position. The original
+	// commit is used in that case so the comment is shown as outdated.
+	line := value.Get("line")
+	commitHash := value.Get("commit_id").String()
+	if typ != preqClient.CommentTypeFile && line.Type == gjson.Null {
+		line = value.Get("original_line")
+		commitHash = value.Get("original_commit_id").String()
+	}
+
+	var beforeLineNumber, afterLineNumber uint
+	if value.Get("side").String() == "LEFT" {
+		beforeLineNumber = uint(line.Uint())
+	} else {
+		afterLineNumber = uint(line.Uint())
+	}
+
+	return &preqClient.PullRequestComment{
+		ID:               value.Get("id").String(),
+		Type:             typ,
+		ParentID:         value.Get("in_reply_to_id").String(),
+		Content:          value.Get("body").String(),
+		Created:          value.Get("created_at").Time(),
+		Updated:          value.Get("updated_at").Time(),
+		User:             value.Get("user.login").String(),
+		BeforeLineNumber: beforeLineNumber,
+		AfterLineNumber:  afterLineNumber,
+		FilePath:         value.Get("path").String(),
+		CommitHash:       commitHash,
+		IsBeingStored:    false,
+		IsBeingDeleted:   false,
+	}
+}
+
+func (c *GithubCloudClient) get(url string) (*resty.Response, error) {
+	r, err := resty.New().R().
+		SetAuthToken(c.token).
+		SetError(githubError{}).
+		Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
+
+	return r, nil
+}
+
+func (c *GithubCloudClient) postComment(
+	url string,
+	body *ghCommentOptions,
+) (*resty.Response, error) {
+	r, err := resty.New().R().
+		SetAuthToken(c.token).
+		SetHeader("content-type", "application/json").
+		SetBody(body).
+		SetError(githubError{}).
+		Post(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.IsError() {
+		return r, errors.New(string(r.Body()))
+	}
+
+	return r, nil
+}
+
 // CreateComment implements client.Client
-func (*GithubCloudClient) CreateComment(
+func (c *GithubCloudClient) CreateComment(
 	o *preqClient.CreateCommentOptions,
 ) (*preqClient.PullRequestComment, error) {
-	panic("unimplemented")
+	if o.ParentRef != nil {
+		r, err := c.postComment(
+			fmt.Sprintf(
+				"%s/repos/%s/pulls/%s/comments/%s/replies",
+				c.baseURL,
+				o.Repository.Name,
+				o.ID,
+				o.ParentRef.ID,
+			),
+			&ghCommentOptions{Body: o.Content},
+		)
+		if err == nil {
+			return parseReviewComment(gjson.ParseBytes(r.Body())), nil
+		}
+
+		// Issue comments cannot be replied to in a thread, the reply
+		// is posted as a new conversation comment instead
+		if r == nil || r.StatusCode() != http.StatusNotFound {
+			return nil, err
+		}
+	} else if o.FilePath != "" {
+		pr, err := c.getPullRequest(o.Repository, o.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		body := &ghCommentOptions{
+			Body:        o.Content,
+			CommitID:    pr.Source.Hash,
+			Path:        o.FilePath,
+			SubjectType: "file",
+		}
+		if o.LineRef != nil {
+			body.SubjectType = "line"
+			body.Line = o.LineRef.LineNumber
+			body.Side = "RIGHT"
+			if o.LineRef.Type == preqClient.OriginalLineNumber {
+				body.Side = "LEFT"
+			}
+		}
+
+		r, err := c.postComment(
+			fmt.Sprintf(
+				"%s/repos/%s/pulls/%s/comments",
+				c.baseURL,
+				o.Repository.Name,
+				o.ID,
+			),
+			body,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return parseReviewComment(gjson.ParseBytes(r.Body())), nil
+	}
+
+	r, err := c.postComment(
+		fmt.Sprintf(
+			"%s/repos/%s/issues/%s/comments",
+			c.baseURL,
+			o.Repository.Name,
+			o.ID,
+		),
+		&ghCommentOptions{Body: o.Content},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseIssueComment(gjson.ParseBytes(r.Body())), nil
+}
+
+// GetDiff returns the unified diff of the pull request as GitHub renders it
+func (c *GithubCloudClient) GetDiff(o *preqClient.GetDiffOptions) ([]byte, error) {
+	r, err := resty.New().R().
+		SetAuthToken(c.token).
+		SetHeader("accept", "application/vnd.github.diff").
+		SetError(githubError{}).
+		Get(fmt.Sprintf("%s/repos/%s/pulls/%s", c.baseURL, o.Repository.Name, o.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
+
+	return r.Body(), nil
 }
 
 // GetComments implements client.Client
-func (*GithubCloudClient) GetComments(
+func (c *GithubCloudClient) GetComments(
 	o *preqClient.GetCommentsOptions,
 ) ([]*preqClient.PullRequestComment, error) {
-	panic("unimplemented")
+	comments := []*preqClient.PullRequestComment{}
+
+	err := c.getAll(
+		fmt.Sprintf(
+			"%s/repos/%s/issues/%s/comments",
+			c.baseURL,
+			o.Repository.Name,
+			o.ID,
+		),
+		func(value gjson.Result) {
+			comments = append(comments, parseIssueComment(value))
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.getAll(
+		fmt.Sprintf(
+			"%s/repos/%s/pulls/%s/comments",
+			c.baseURL,
+			o.Repository.Name,
+			o.ID,
+		),
+		func(value gjson.Result) {
+			comments = append(comments, parseReviewComment(value))
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (c *GithubCloudClient) deleteComment(url string) (*resty.Response, error) {
+	r, err := resty.New().R().
+		SetAuthToken(c.token).
+		SetError(githubError{}).
+		Delete(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.IsError() {
+		return r, errors.New(string(r.Body()))
+	}
+
+	return r, nil
 }
 
 // DeleteComment implements client.Client
-func (*GithubCloudClient) DeleteComment(o *preqClient.DeleteCommentOptions) error {
-	panic("unimplemented")
+func (c *GithubCloudClient) DeleteComment(o *preqClient.DeleteCommentOptions) error {
+	r, err := c.deleteComment(fmt.Sprintf(
+		"%s/repos/%s/pulls/comments/%s",
+		c.baseURL,
+		o.Repository.Name,
+		o.CommentID,
+	))
+	if err == nil {
+		return nil
+	}
+
+	// Review and issue comments live in different endpoints, the comment
+	// is not a review comment when it cannot be found
+	if r == nil || r.StatusCode() != http.StatusNotFound {
+		return err
+	}
+
+	_, err = c.deleteComment(fmt.Sprintf(
+		"%s/repos/%s/issues/comments/%s",
+		c.baseURL,
+		o.Repository.Name,
+		o.CommentID,
+	))
+
+	return err
+}
+
+// getNextPageURL extracts the URL of the next page from a Link header, e.g.
+// `<https://api.github.com/...&page=2>; rel="next", <...>; rel="last"`
+func getNextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(segments[0]), "<>")
+			}
+		}
+	}
+
+	return ""
+}
+
+// getAll calls fn for every value of a paged list response
+// following the Link headers until the last page is reached
+func (c *GithubCloudClient) getAll(
+	url string,
+	fn func(value gjson.Result),
+) error {
+	r, err := resty.New().R().
+		SetAuthToken(c.token).
+		SetQueryParam("per_page", "100").
+		SetError(githubError{}).
+		Get(url)
+
+	for {
+		if err != nil {
+			return err
+		}
+		if r.IsError() {
+			return errors.New(string(r.Body()))
+		}
+
+		gjson.ParseBytes(r.Body()).ForEach(func(key, value gjson.Result) bool {
+			fn(value)
+			return true
+		})
+
+		next := getNextPageURL(r.Header().Get("Link"))
+		if next == "" {
+			return nil
+		}
+
+		r, err = resty.New().R().
+			SetAuthToken(c.token).
+			SetError(githubError{}).
+			Get(next)
+	}
+}
+
+func stateQueryParam(s preqClient.PullRequestState) string {
+	switch s {
+	case preqClient.PullRequestState_OPEN:
+		return "open"
+	case preqClient.PullRequestState_MERGED, preqClient.PullRequestState_DECLINED:
+		return "closed"
+	}
+
+	return "all"
+}
+
+// parseState maps GitHub's open/closed states, closed pull requests
+// which have been merged are considered as merged instead of declined
+func parseState(value gjson.Result) preqClient.PullRequestState {
+	if value.Get("state").String() == "open" {
+		return preqClient.PullRequestState_OPEN
+	}
+
+	mergedAt := value.Get("merged_at")
+	if value.Get("merged").Bool() || (mergedAt.Exists() && mergedAt.Type != gjson.Null) {
+		return preqClient.PullRequestState_MERGED
+	}
+
+	return preqClient.PullRequestState_DECLINED
+}
+
+func parsePullRequest(value gjson.Result) *preqClient.PullRequest {
+	return &preqClient.PullRequest{
+		ID:          value.Get("number").String(),
+		Title:       value.Get("title").String(),
+		Description: value.Get("body").String(),
+		User:        value.Get("user.login").String(),
+		URL:         value.Get("html_url").String(),
+		State:       parseState(value),
+		IsDraft:     value.Get("draft").Bool(),
+		Source: preqClient.PullRequestBranch{
+			Name:       value.Get("head.ref").String(),
+			Hash:       value.Get("head.sha").String(),
+			Repository: value.Get("head.repo.full_name").String(),
+		},
+		Destination: preqClient.PullRequestBranch{
+			Name:       value.Get("base.ref").String(),
+			Hash:       value.Get("base.sha").String(),
+			Repository: value.Get("base.repo.full_name").String(),
+		},
+		// Comment counts are only included when a single pull
+		// request is fetched
+		CommentCount: int(value.Get("comments").Int() + value.Get("review_comments").Int()),
+		Created:      value.Get("created_at").Time(),
+		Updated:      value.Get("updated_at").Time(),
+	}
+}
+
+// needsSearch reports whether the filter can only be applied by the search
+// API, the pull request list only filters by the branches
+func needsSearch(f *preqClient.PullRequestFilter) bool {
+	return f != nil &&
+		(f.Author != "" || f.Reviewer != "" || !f.UpdatedSince.IsZero() || f.Search != "")
+}
+
+// searchQuery builds the issue search query of the filter, see
+// https://docs.github.com/en/search-github/searching-on-github/searching-issues-and-pull-requests
+func searchQuery(
+	repo *preqClient.Repository,
+	state preqClient.PullRequestState,
+	f *preqClient.PullRequestFilter,
+) string {
+	parts := []string{fmt.Sprintf("repo:%s", repo.Name), "is:pr"}
+	switch state {
+	case preqClient.PullRequestState_OPEN:
+		parts = append(parts, "is:open")
+	case preqClient.PullRequestState_MERGED:
+		parts = append(parts, "is:merged")
+	case preqClient.PullRequestState_DECLINED:
+		parts = append(parts, "is:closed", "is:unmerged")
+	}
+
+	if f.Author != "" {
+		parts = append(parts, fmt.Sprintf("author:%s", f.Author))
+	}
+	if f.Reviewer != "" {
+		parts = append(parts, fmt.Sprintf("review-requested:%s", f.Reviewer))
+	}
+	if f.Source != "" {
+		parts = append(parts, fmt.Sprintf("head:%s", f.Source))
+	}
+	if f.Destination != "" {
+		parts = append(parts, fmt.Sprintf("base:%s", f.Destination))
+	}
+	if !f.UpdatedSince.IsZero() {
+		parts = append(parts, fmt.Sprintf("updated:>=%s", f.UpdatedSince.UTC().Format(time.RFC3339)))
+	}
+	if f.Search != "" {
+		parts = append(parts, f.Search, "in:title,body")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// searchPullRequests lists the pull requests with the search API, the
+// results lack the branches so every pull request is fetched as well
+func (c *GithubCloudClient) searchPullRequests(
+	o *preqClient.GetPullRequestsOptions,
+) (*preqClient.PullRequestList, error) {
+	search := newClient(&newClientOptions{Token: c.token, BaseURL: c.baseURL}).Search
+
+	var (
+		res *IssuesSearchResult
+		err error
+	)
+	if o.Next != "" {
+		res, err = search.IssuesNext(context.Background(), o.Next)
+	} else {
+		res, err = search.Issues(context.Background(), searchQuery(o.Repository, o.State, o.Filter))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := c.getSearchItemPullRequests(o.Repository, res.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	return &preqClient.PullRequestList{
+		PageLength: uint(len(res.Items)),
+		Size:       uint(res.TotalCount),
+		NextURL:    res.NextURL,
+		Values:     values,
+	}, nil
+}
+
+// getSearchItemPullRequests fetches the pull requests of the search
+// results, in the order of the results
+func (c *GithubCloudClient) getSearchItemPullRequests(
+	repo *preqClient.Repository,
+	items []*Item,
+) ([]*preqClient.PullRequest, error) {
+	values := make([]*preqClient.PullRequest, len(items))
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		// Limits the concurrent requests to stay clear of the secondary
+		// rate limits
+		sem = make(chan struct{}, 5)
+	)
+	for i, item := range items {
+		wg.Add(1)
+		go func(i int, item *Item) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			pr, err := c.getPullRequest(repo, fmt.Sprint(item.Number))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			values[i] = pr
+		}(i, item)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return values, nil
 }
 
 func (c *GithubCloudClient) GetPullRequests(
 	o *preqClient.GetPullRequestsOptions,
 ) (*preqClient.PullRequestList, error) {
+	if o.State == preqClient.PullRequestState_SUPERSEDED {
+		return nil, preqClient.ErrUnsupportedState
+	}
+
+	if needsSearch(o.Filter) {
+		return c.searchPullRequests(o)
+	}
+
 	url := fmt.Sprintf(
-		"https://api.github.com/repos/%s/pulls",
+		"%s/repos/%s/pulls",
+		c.baseURL,
 		o.Repository.Name,
 	)
 
+	request := resty.New().R().
+		SetAuthToken(c.token).
+		SetError(githubError{})
+
+	// The next page URL already contains all the query parameters
 	if o.Next != "" {
 		url = o.Next
+	} else {
+		request.SetQueryParam("state", stateQueryParam(o.State))
+		if o.Filter != nil && o.Filter.Destination != "" {
+			request.SetQueryParam("base", o.Filter.Destination)
+		}
 	}
 
-	rc := resty.New()
-	r, err := rc.R().
-		SetAuthToken(c.token).
-		SetQueryParam("state", string(o.State)).
-		SetError(bbError{}).
-		Get(url)
+	r, err := request.Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -156,58 +673,34 @@ func (c *GithubCloudClient) GetPullRequests(
 	var pr preqClient.PullRequestList
 	parsed := gjson.ParseBytes(r.Body())
 	parsed.ForEach(func(key, value gjson.Result) bool {
-		pr.Values = append(pr.Values, &preqClient.PullRequest{
-			ID:    value.Get("number").String(),
-			Title: value.Get("title").String(),
-			URL:   value.Get("html_url").String(),
-			State: preqClient.PullRequestState(
-				value.Get("state").String(),
-			),
-			Source: preqClient.PullRequestBranch{
-				Name: value.Get("head.ref").String(),
-				Hash: "123",
-			},
-			Destination: preqClient.PullRequestBranch{
-				Name: value.Get("base.ref").String(),
-				Hash: "123",
-			},
-			Created: value.Get("created_at").Time(),
-			Updated: value.Get("updated_at").Time(),
-		})
+		v := parsePullRequest(value)
+		// Merged and declined pull requests share the closed state
+		if o.State != "" && stateQueryParam(o.State) == "closed" && v.State != o.State {
+			return true
+		}
 
+		// The source branch is only filtered by the owner:branch form,
+		// which is unknown for forks
+		if !o.Filter.Matches(v) {
+			return true
+		}
+
+		pr.Values = append(pr.Values, v)
 		return true
 	})
 
-	// pr.PageLength = uint(parsed.Get("pagelen").Uint())
-	// pr.Page = uint(parsed.Get("page").Uint())
-	// pr.Size = uint(parsed.Get("size").Uint())
-
-	// pr.NextURL = r.Header().Get("Link") // Split on , -> split on ; check for rel="next"
+	pr.PageLength = uint(parsed.Get("#").Uint())
+	pr.NextURL = getNextPageURL(r.Header().Get("Link"))
 
 	return &pr, nil
 }
 
 func unmarshalPR(data []byte) (*preqClient.PullRequest, error) {
-	pr := &PullRequest{}
-	err := json.Unmarshal(data, pr)
-	if err != nil {
-		return nil, err
+	if !gjson.ValidBytes(data) {
+		return nil, errors.New("invalid pull request response")
 	}
 
-	return &preqClient.PullRequest{
-		ID:    fmt.Sprint(pr.Number),
-		Title: pr.Title,
-		URL:   pr.Links.HTML.Href,
-		State: preqClient.PullRequestState(pr.State),
-		Source: preqClient.PullRequestBranch{
-			Name: pr.Head.Ref,
-			Hash: pr.Head.SHA,
-		},
-		Destination: preqClient.PullRequestBranch{
-			Name: pr.Base.Ref,
-			Hash: pr.Base.SHA,
-		},
-	}, nil
+	return parsePullRequest(gjson.ParseBytes(data)), nil
 }
 
 func (c *GithubCloudClient) post(url string) (*resty.Response, error) {
@@ -227,10 +720,199 @@ func (c *GithubCloudClient) post(url string) (*resty.Response, error) {
 	return r, nil
 }
 
+type ghMergeOptions struct {
+	CommitTitle   string `json:"commit_title,omitempty"`
+	CommitMessage string `json:"commit_message,omitempty"`
+	MergeMethod   string `json:"merge_method,omitempty"`
+}
+
+func mergeMethod(s preqClient.MergeStrategy) string {
+	switch s {
+	case preqClient.MergeStrategy_MERGE_COMMIT:
+		return "merge"
+	case preqClient.MergeStrategy_SQUASH:
+		return "squash"
+	case preqClient.MergeStrategy_FAST_FORWARD:
+		return "rebase"
+	}
+
+	return ""
+}
+
 func (c *GithubCloudClient) Merge(
 	o *preqClient.MergeOptions,
 ) (*preqClient.PullRequest, error) {
-	return nil, ErrMissingGithubPassword
+	options := ghMergeOptions{MergeMethod: mergeMethod(o.Strategy)}
+	if o.Message != "" {
+		// The first line of the message is used as the commit title
+		lines := strings.SplitN(o.Message, "\n", 2)
+		options.CommitTitle = lines[0]
+		if len(lines) > 1 {
+			options.CommitMessage = strings.TrimSpace(lines[1])
+		}
+	}
+
+	r, err := resty.New().R().
+		SetAuthToken(c.token).
+		SetHeader("content-type", "application/json").
+		SetBody(options).
+		SetError(githubError{}).
+		Put(fmt.Sprintf(
+			"%s/repos/%s/pulls/%s/merge",
+			c.baseURL,
+			o.Repository.Name,
+			o.ID,
+		))
+	if err != nil {
+		return nil, err
+	}
+
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
+
+	if !gjson.GetBytes(r.Body(), "merged").Bool() {
+		return nil, errors.New(gjson.GetBytes(r.Body(), "message").String())
+	}
+
+	return &preqClient.PullRequest{
+		ID:    o.ID,
+		State: preqClient.PullRequestState_MERGED,
+	}, nil
+}
+
+type ghPRUpdateOptions struct {
+	Title *string `json:"title,omitempty"`
+	Body  *string `json:"body,omitempty"`
+	Base  *string `json:"base,omitempty"`
+}
+
+type ghReviewRequestOptions struct {
+	Reviewers []string `json:"reviewers"`
+}
+
+func (c *GithubCloudClient) send(
+	method string,
+	url string,
+	body interface{},
+) (*resty.Response, error) {
+	r, err := resty.New().R().
+		SetAuthToken(c.token).
+		SetHeader("content-type", "application/json").
+		SetBody(body).
+		SetError(githubError{}).
+		Execute(method, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
+
+	return r, nil
+}
+
+// graphqlURL returns the GraphQL endpoint, GitHub Enterprise serves it
+// at /api/graphql next to the /api/v3 REST API
+func (c *GithubCloudClient) graphqlURL() string {
+	return fmt.Sprintf("%s/graphql", strings.TrimSuffix(c.baseURL, "/v3"))
+}
+
+// setDraft converts the pull request to a draft or marks it ready for
+// review, which is only possible with the GraphQL API
+func (c *GithubCloudClient) setDraft(
+	repo *preqClient.Repository,
+	id string,
+	draft bool,
+) error {
+	r, err := c.get(fmt.Sprintf(
+		"%s/repos/%s/pulls/%s",
+		c.baseURL,
+		repo.Name,
+		id,
+	))
+	if err != nil {
+		return err
+	}
+
+	mutation := "markPullRequestReadyForReview"
+	if draft {
+		mutation = "convertPullRequestToDraft"
+	}
+
+	r, err = c.send(resty.MethodPost, c.graphqlURL(), map[string]interface{}{
+		"query": fmt.Sprintf(
+			`mutation($id: ID!) { %s(input: {pullRequestId: $id}) { pullRequest { id } } }`,
+			mutation,
+		),
+		"variables": map[string]string{
+			"id": gjson.GetBytes(r.Body(), "node_id").String(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// GraphQL errors are returned with a successful status code
+	if message := gjson.GetBytes(r.Body(), "errors.0.message"); message.Exists() {
+		return errors.New(message.String())
+	}
+
+	return nil
+}
+
+func (c *GithubCloudClient) UpdatePullRequest(
+	o *preqClient.UpdatePullRequestOptions,
+) (*preqClient.PullRequest, error) {
+	url := fmt.Sprintf(
+		"%s/repos/%s/pulls/%s",
+		c.baseURL,
+		o.Repository.Name,
+		o.ID,
+	)
+
+	if o.Title != nil || o.Description != nil || o.Destination != nil {
+		_, err := c.send(resty.MethodPatch, url, ghPRUpdateOptions{
+			Title: o.Title,
+			Body:  o.Description,
+			Base:  o.Destination,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(o.AddReviewers) > 0 {
+		_, err := c.send(
+			resty.MethodPost,
+			fmt.Sprintf("%s/requested_reviewers", url),
+			ghReviewRequestOptions{Reviewers: o.AddReviewers},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(o.RemoveReviewers) > 0 {
+		_, err := c.send(
+			resty.MethodDelete,
+			fmt.Sprintf("%s/requested_reviewers", url),
+			ghReviewRequestOptions{Reviewers: o.RemoveReviewers},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if o.Draft != nil {
+		err := c.setDraft(o.Repository, o.ID, *o.Draft)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c.getPullRequest(o.Repository, o.ID)
 }
 
 func (c *GithubCloudClient) DeclinePullRequest(
@@ -243,7 +925,8 @@ func (c *GithubCloudClient) DeclinePullRequest(
 		}).
 		SetError(bbError{}).
 		Patch(fmt.Sprintf(
-			"https://api.github.com/repos/%s/pulls/%s",
+			"%s/repos/%s/pulls/%s",
+			c.baseURL,
 			o.Repository.Name,
 			o.ID,
 		))
@@ -254,10 +937,27 @@ func (c *GithubCloudClient) DeclinePullRequest(
 	return unmarshalPR(r.Body())
 }
 
+func (c *GithubCloudClient) getPullRequest(
+	repo *preqClient.Repository,
+	id string,
+) (*preqClient.PullRequest, error) {
+	r, err := c.get(fmt.Sprintf(
+		"%s/repos/%s/pulls/%s",
+		c.baseURL,
+		repo.Name,
+		id,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalPR(r.Body())
+}
+
 func (c *GithubCloudClient) GetPullRequestInfo(
 	o *preqClient.ApproveOptions,
 ) (*preqClient.PullRequest, error) {
-	return nil, nil
+	return c.getPullRequest(o.Repository, o.ID)
 }
 
 type getReviewsOptions struct {
@@ -295,14 +995,12 @@ type reviewRequest struct {
 func (c *GithubCloudClient) getReviewRequests(
 	o *getReviewsOptions,
 ) ([]int64, error) {
-	r, err := resty.New().R().
-		SetAuthToken(c.token).
-		SetError(githubError{}).
-		Get(fmt.Sprintf(
-			"https://api.github.com/repos/%s/pulls/%s/requested_reviewers",
-			o.Repository.Name,
-			o.ID,
-		))
+	r, err := c.get(fmt.Sprintf(
+		"%s/repos/%s/pulls/%s/requested_reviewers",
+		c.baseURL,
+		o.Repository.Name,
+		o.ID,
+	))
 	if err != nil {
 		return nil, err
 	}
@@ -326,27 +1024,76 @@ func (c *GithubCloudClient) getReviewRequests(
 func (c *GithubCloudClient) getReviews(
 	o *getReviewsOptions,
 ) (*[]review, error) {
-	r, err := resty.New().R().
-		SetAuthToken(c.token).
-		SetError(githubError{}).
-		Get(fmt.Sprintf(
-			"https://api.github.com/repos/%s/pulls/%s/reviews",
+	reviews := []review{}
+	var unmarshalErr error
+	err := c.getAll(
+		fmt.Sprintf(
+			"%s/repos/%s/pulls/%s/reviews",
+			c.baseURL,
 			o.Repository.Name,
 			o.ID,
-		))
+		),
+		func(value gjson.Result) {
+			rv := review{}
+			err := json.Unmarshal([]byte(value.Raw), &rv)
+			if err != nil {
+				unmarshalErr = err
+				return
+			}
+
+			if o.User == "" || rv.User.Login == o.User {
+				reviews = append(reviews, rv)
+			}
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
 
-	reviews := &[]review{}
-	err = json.Unmarshal(r.Body(), reviews)
-	if err != nil {
-		return nil, err
+	return &reviews, nil
+}
+
+// latestReviews collapses the reviews to the latest review state of
+// each reviewer. Plain comments do not override an earlier approval or
+// change request and a dismissed review clears the reviewer's state.
+// Reviewers whose review was re-requested are left out, since their
+// earlier review no longer applies.
+func latestReviews(reviews []review, requested []int64) []review {
+	isRequested := map[int64]bool{}
+	for _, id := range requested {
+		isRequested[id] = true
+	}
+
+	latest := map[int64]review{}
+	ordered := map[int64]bool{}
+	order := []int64{}
+	for _, rv := range reviews {
+		switch rv.State {
+		case "APPROVED", "CHANGES_REQUESTED":
+			if !ordered[rv.User.ID] {
+				ordered[rv.User.ID] = true
+				order = append(order, rv.User.ID)
+			}
+			latest[rv.User.ID] = rv
+		case "DISMISSED":
+			delete(latest, rv.User.ID)
+		}
 	}
 
-	fmt.Println(o.ID, string(r.Body()))
+	res := []review{}
+	for _, id := range order {
+		rv, ok := latest[id]
+		if !ok || isRequested[id] {
+			continue
+		}
+
+		res = append(res, rv)
+	}
 
-	return reviews, nil
+	return res
 }
 
 func (c *GithubCloudClient) Unapprove(
@@ -383,7 +1130,8 @@ func (c *GithubCloudClient) Approve(
 		SetError(githubError{}).
 		SetBody(`{"event": "APPROVE"}`).
 		Post(fmt.Sprintf(
-			"https://api.github.com/repos/%s/pulls/%s/reviews",
+			"%s/repos/%s/pulls/%s/reviews",
+			c.baseURL,
 			o.Repository.Name,
 			o.ID,
 		))
@@ -413,29 +1161,60 @@ func verifyCreatePullRequestOptions(
 	return nil
 }
 
-func (c *GithubCloudClient) getReviewRequestsForUser(u *User) ([]*Item, error) {
-	client := newClient(&newClientOptions{
-		Token: c.token,
-	})
-
-	res, err := client.Search.Issues(
+// getReviewRequestsForUser searches the open pull requests of the
+// repository with a pending review request of the user, the request is
+// removed once the user reviews the pull request
+func (c *GithubCloudClient) getReviewRequestsForUser(
+	repo *preqClient.Repository,
+	login string,
+) ([]*Item, error) {
+	search := newClient(&newClientOptions{
+		Token:   c.token,
+		BaseURL: c.baseURL,
+	}).Search
+
+	res, err := search.Issues(
 		context.Background(),
-		// fmt.Sprintf("repo:%s type:pr state:open review-requested:%s", u.Login),
-		fmt.Sprintf("type:pr state:open review-requested:%s", u.Login),
+		searchQuery(repo, preqClient.PullRequestState_OPEN, &preqClient.PullRequestFilter{Reviewer: login}),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, r := range res.Items {
-		fmt.Println(r.ID)
+	items := res.Items
+	for res.NextURL != "" {
+		res, err = search.IssuesNext(context.Background(), res.NextURL)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, res.Items...)
+	}
+
+	return items, nil
+}
+
+func (c *GithubCloudClient) GetReviewRequests(
+	o *preqClient.GetReviewRequestsOptions,
+) ([]*preqClient.PullRequest, error) {
+	user := o.User
+	if user == nil {
+		var err error
+		user, err = c.GetCurrentUser()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := c.getReviewRequestsForUser(o.Repository, user.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	return res.Items, nil
+	return c.getSearchItemPullRequests(o.Repository, items)
 }
 
 func (c *GithubCloudClient) GetCurrentUser() (*preqClient.User, error) {
-	client := newClient(&newClientOptions{Token: c.token})
+	client := newClient(&newClientOptions{Token: c.token, BaseURL: c.baseURL})
 
 	u, err := client.User.Current(context.Background())
 	if err != nil {
@@ -443,7 +1222,9 @@ func (c *GithubCloudClient) GetCurrentUser() (*preqClient.User, error) {
 	}
 
 	return &preqClient.User{
-		ID: fmt.Sprint(u.ID),
+		ID:       u.Login,
+		Username: u.Login,
+		Name:     u.Name,
 	}, nil
 }
 
@@ -451,6 +1232,41 @@ func (c *GithubCloudClient) FillMiscInfoAsync(
 	repo *preqClient.Repository,
 	pr *preqClient.PullRequest,
 ) error {
+	o := &getReviewsOptions{Repository: *repo, ID: pr.ID}
+	reviews, err := c.getReviews(o)
+	if err != nil {
+		return err
+	}
+
+	requested, err := c.getReviewRequests(o)
+	if err != nil {
+		return err
+	}
+
+	info, err := c.getPullRequest(repo, pr.ID)
+	if err != nil {
+		return err
+	}
+	pr.CommentCount = info.CommentCount
+
+	pr.Approvals = []*preqClient.PullRequestApproval{}
+	pr.ChangesRequests = []*preqClient.PullRequestChangesRequest{}
+	for _, rv := range latestReviews(*reviews, requested) {
+		created, _ := time.Parse(time.RFC3339, rv.SubmittedAt)
+		switch rv.State {
+		case "APPROVED":
+			pr.Approvals = append(pr.Approvals, &preqClient.PullRequestApproval{
+				Created: created,
+				User:    rv.User.Login,
+			})
+		case "CHANGES_REQUESTED":
+			pr.ChangesRequests = append(pr.ChangesRequests, &preqClient.PullRequestChangesRequest{
+				Created: created,
+				User:    rv.User.Login,
+			})
+		}
+	}
+
 	return nil
 }
 
@@ -467,13 +1283,16 @@ func (c *GithubCloudClient) CreatePullRequest(
 		// SetHeader("content-type", "application/json").
 		SetBody(ghPROptions{
 			Title: o.Title,
+			Body:  o.Description,
+			Draft: o.Draft,
 			// CloseSourceBranch: o.CloseBranch,
 			Head: o.Source,
 			Base: o.Destination,
 		}).
 		SetError(bbError{}).
 		Post(fmt.Sprintf(
-			"https://api.github.com/repos/%s/pulls",
+			"%s/repos/%s/pulls",
+			c.baseURL,
 			o.Repository.Name,
 		))
 	if err != nil {
@@ -488,11 +1307,34 @@ func (c *GithubCloudClient) CreatePullRequest(
 		log.Fatal().Err(err).Msg(("error while unmarshalling a pull request"))
 	}
 
+	// GitHub applies the code owners by itself, other reviewers have to be
+	// requested after the pull request is created
+	if reviewers := o.WithoutAuthor(); len(reviewers) > 0 {
+		_, err = c.send(
+			resty.MethodPost,
+			fmt.Sprintf(
+				"%s/repos/%s/pulls/%d/requested_reviewers",
+				c.baseURL,
+				o.Repository.Name,
+				pr.Number,
+			),
+			ghReviewRequestOptions{Reviewers: reviewers},
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"pull request %s was created, but its reviewers could not be requested: %w",
+				pr.Links.HTML.Href,
+				err,
+			)
+		}
+	}
+
 	return &preqClient.PullRequest{
-		ID:    fmt.Sprint(pr.Number),
-		Title: pr.Title,
-		URL:   pr.Links.HTML.Href,
-		State: preqClient.PullRequestState(pr.State),
+		ID:      fmt.Sprint(pr.Number),
+		Title:   pr.Title,
+		URL:     pr.Links.HTML.Href,
+		State:   preqClient.PullRequestState(pr.State),
+		IsDraft: pr.Draft,
 		Source: preqClient.PullRequestBranch{
 			Name: pr.Head.Ref,
 			Hash: pr.Head.SHA,
@@ -503,3 +1345,30 @@ func (c *GithubCloudClient) CreatePullRequest(
 		},
 	}, nil
 }
+
+// SearchUsers finds the users who can be assigned to the issues and pull
+// requests of the repository, i.e. the collaborators who can review them
+func (c *GithubCloudClient) SearchUsers(
+	o *preqClient.SearchUsersOptions,
+) ([]*preqClient.User, error) {
+	users := []*preqClient.User{}
+	err := c.getAll(
+		fmt.Sprintf("%s/repos/%s/assignees", c.baseURL, o.Repository.Name),
+		func(value gjson.Result) {
+			login := value.Get("login").String()
+			if matchesLogin(login, o.Query) {
+				users = append(users, &preqClient.User{ID: login, Username: login})
+			}
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// matchesLogin reports whether the login contains the query, ignoring case
+func matchesLogin(login string, query string) bool {
+	return strings.Contains(strings.ToLower(login), strings.ToLower(query))
+}
@@ -1,7 +1,197 @@
 package github
 
+import (
+	"fmt"
+	preqClient "preq/internal/pkg/client"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
 // func TestASD(t *testing.T) {
 // 	c := New()
 // 	c.GetPullRequests()
 // 	// assert.IsType(t, client, c)
 // }
+
+func Test_parseReviewComment(t *testing.T) {
+	t.Run("maps the right side to the new line number", func(t *testing.T) {
+		c := parseReviewComment(gjson.Parse(`{
+			"id": 1, "path": "a.go", "line": 12, "side": "RIGHT",
+			"commit_id": "new", "original_commit_id": "old"
+		}`))
+		assert.Equal(t, preqClient.CommentType(preqClient.CommentTypeInline), c.Type)
+		assert.Equal(t, uint(0), c.BeforeLineNumber)
+		assert.Equal(t, uint(12), c.AfterLineNumber)
+		assert.Equal(t, "new", c.CommitHash)
+	})
+
+	t.Run("maps the left side to the original line number", func(t *testing.T) {
+		c := parseReviewComment(gjson.Parse(`{
+			"id": 1, "path": "a.go", "line": 3, "side": "LEFT", "commit_id": "new"
+		}`))
+		assert.Equal(t, uint(3), c.BeforeLineNumber)
+		assert.Equal(t, uint(0), c.AfterLineNumber)
+	})
+
+	t.Run("uses the original position for outdated comments", func(t *testing.T) {
+		c := parseReviewComment(gjson.Parse(`{
+			"id": 1, "path": "a.go", "line": null, "original_line": 7,
+			"side": "RIGHT", "commit_id": "new", "original_commit_id": "old"
+		}`))
+		assert.Equal(t, uint(7), c.AfterLineNumber)
+		assert.True(t, c.IsOutdated("new"))
+	})
+
+	t.Run("sets the parent of replies", func(t *testing.T) {
+		c := parseReviewComment(gjson.Parse(`{
+			"id": 2, "in_reply_to_id": 1, "path": "a.go", "line": 3, "side": "RIGHT"
+		}`))
+		assert.Equal(t, preqClient.CommentType(preqClient.CommentTypeReply), c.Type)
+		assert.Equal(t, "1", c.ParentID)
+	})
+
+	t.Run("recognizes file comments", func(t *testing.T) {
+		c := parseReviewComment(gjson.Parse(`{
+			"id": 1, "path": "a.go", "line": null, "subject_type": "file",
+			"commit_id": "new"
+		}`))
+		assert.Equal(t, preqClient.CommentType(preqClient.CommentTypeFile), c.Type)
+		assert.False(t, c.IsOutdated("new"))
+	})
+}
+
+func Test_getNextPageURL(t *testing.T) {
+	t.Run("returns the next page URL", func(t *testing.T) {
+		v := getNextPageURL(
+			`<https://api.github.com/repositories/1/pulls?page=2>; rel="next", ` +
+				`<https://api.github.com/repositories/1/pulls?page=5>; rel="last"`,
+		)
+		assert.Equal(t, "https://api.github.com/repositories/1/pulls?page=2", v)
+	})
+
+	t.Run("returns empty string on the last page", func(t *testing.T) {
+		v := getNextPageURL(
+			`<https://api.github.com/repositories/1/pulls?page=1>; rel="first", ` +
+				`<https://api.github.com/repositories/1/pulls?page=4>; rel="prev"`,
+		)
+		assert.Equal(t, "", v)
+	})
+
+	t.Run("returns empty string without a header", func(t *testing.T) {
+		assert.Equal(t, "", getNextPageURL(""))
+	})
+}
+
+func Test_parseState(t *testing.T) {
+	t.Run("open pull requests are open", func(t *testing.T) {
+		v := parseState(gjson.Parse(`{"state": "open", "merged_at": null}`))
+		assert.Equal(t, preqClient.PullRequestState(preqClient.PullRequestState_OPEN), v)
+	})
+
+	t.Run("closed pull requests with a merge date are merged", func(t *testing.T) {
+		v := parseState(gjson.Parse(`{"state": "closed", "merged_at": "2023-01-01T00:00:00Z"}`))
+		assert.Equal(t, preqClient.PullRequestState(preqClient.PullRequestState_MERGED), v)
+	})
+
+	t.Run("closed pull requests without a merge date are declined", func(t *testing.T) {
+		v := parseState(gjson.Parse(`{"state": "closed", "merged_at": null}`))
+		assert.Equal(t, preqClient.PullRequestState(preqClient.PullRequestState_DECLINED), v)
+	})
+}
+
+func Test_latestReviews(t *testing.T) {
+	newReview := func(id int64, state string) review {
+		return review{User: user{ID: id, Login: fmt.Sprint(id)}, State: state}
+	}
+
+	t.Run("keeps the latest state of each reviewer", func(t *testing.T) {
+		v := latestReviews([]review{
+			newReview(1, "CHANGES_REQUESTED"),
+			newReview(2, "APPROVED"),
+			newReview(1, "APPROVED"),
+		}, nil)
+		assert.Equal(t, []review{
+			newReview(1, "APPROVED"),
+			newReview(2, "APPROVED"),
+		}, v)
+	})
+
+	t.Run("comments do not override an approval", func(t *testing.T) {
+		v := latestReviews([]review{
+			newReview(1, "APPROVED"),
+			newReview(1, "COMMENTED"),
+		}, nil)
+		assert.Equal(t, []review{newReview(1, "APPROVED")}, v)
+	})
+
+	t.Run("dismissed reviews are dropped", func(t *testing.T) {
+		v := latestReviews([]review{
+			newReview(1, "CHANGES_REQUESTED"),
+			newReview(1, "DISMISSED"),
+		}, nil)
+		assert.Equal(t, []review{}, v)
+	})
+
+	t.Run("approving again after a dismissal counts once", func(t *testing.T) {
+		v := latestReviews([]review{
+			newReview(1, "APPROVED"),
+			newReview(1, "DISMISSED"),
+			newReview(1, "APPROVED"),
+		}, nil)
+		assert.Equal(t, []review{newReview(1, "APPROVED")}, v)
+	})
+
+	t.Run("re-requested reviewers are dropped", func(t *testing.T) {
+		v := latestReviews([]review{
+			newReview(1, "APPROVED"),
+			newReview(2, "APPROVED"),
+		}, []int64{2})
+		assert.Equal(t, []review{newReview(1, "APPROVED")}, v)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("defaults to the public API", func(t *testing.T) {
+		c := New(&ClientOptions{}).(*GithubCloudClient)
+		assert.Equal(t, DefaultBaseURL, c.baseURL)
+	})
+
+	t.Run("uses the configured base URL", func(t *testing.T) {
+		c := New(&ClientOptions{BaseURL: "https://github.example.com/api/v3/"}).(*GithubCloudClient)
+		assert.Equal(t, "https://github.example.com/api/v3", c.baseURL)
+	})
+}
+
+func Test_searchQuery(t *testing.T) {
+	repo := &preqClient.Repository{Name: "owner/repo"}
+
+	assert.Equal(
+		t,
+		"repo:owner/repo is:pr is:closed is:unmerged author:alice review-requested:bob base:main "+
+			"updated:>=2023-03-01T00:00:00Z parser in:title,body",
+		searchQuery(repo, preqClient.PullRequestState_DECLINED, &preqClient.PullRequestFilter{
+			Author:       "alice",
+			Reviewer:     "bob",
+			Destination:  "main",
+			UpdatedSince: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			Search:       "parser",
+		}),
+	)
+	assert.Equal(t, "repo:owner/repo is:pr head:fix", searchQuery(repo, "", &preqClient.PullRequestFilter{Source: "fix"}))
+}
+
+func Test_needsSearch(t *testing.T) {
+	assert.False(t, needsSearch(nil))
+	assert.False(t, needsSearch(&preqClient.PullRequestFilter{Source: "fix", Destination: "main"}))
+	assert.True(t, needsSearch(&preqClient.PullRequestFilter{Author: "alice"}))
+	assert.True(t, needsSearch(&preqClient.PullRequestFilter{Search: "parser"}))
+}
+
+func Test_matchesLogin(t *testing.T) {
+	assert.True(t, matchesLogin("octocat", "octocat"))
+	assert.True(t, matchesLogin("OctoCat", "cat"))
+	assert.False(t, matchesLogin("octocat", "dog"))
+}
@@ -0,0 +1,943 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	preqClient "preq/internal/pkg/client"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/tidwall/gjson"
+)
+
+const DefaultBaseURL = "https://gitlab.com/api/v4"
+
+var (
+	ErrUnsupportedMergeStrategy = errors.New(strings.TrimSpace(`
+		fast-forward merges are configured per project on GitLab, use the default strategy instead
+	`))
+	ErrInvalidCommentID = errors.New("invalid comment ID, expected (discussion/note)")
+)
+
+type GitlabClient struct {
+	token   string
+	baseURL string
+}
+
+type ClientOptions struct {
+	Token string
+	// BaseURL is the API URL of the GitLab instance, defaults to
+	// DefaultBaseURL
+	BaseURL string
+}
+
+func New(o *ClientOptions) preqClient.Client {
+	baseURL := strings.TrimSuffix(o.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &GitlabClient{
+		token:   o.Token,
+		baseURL: baseURL,
+	}
+}
+
+// mergeRequestsURL returns the API URL of the repository's merge requests.
+// GitLab accepts the URL encoded project path in place of the project ID.
+func (c *GitlabClient) mergeRequestsURL(repo *preqClient.Repository) string {
+	return fmt.Sprintf(
+		"%s/projects/%s/merge_requests",
+		c.baseURL,
+		url.PathEscape(repo.Name),
+	)
+}
+
+func (c *GitlabClient) mergeRequestURL(
+	repo *preqClient.Repository,
+	id string,
+) string {
+	return fmt.Sprintf("%s/%s", c.mergeRequestsURL(repo), id)
+}
+
+func (c *GitlabClient) request() *resty.Request {
+	return resty.New().R().
+		SetHeader("PRIVATE-TOKEN", c.token).
+		SetError(glError{})
+}
+
+func (c *GitlabClient) send(
+	method string,
+	url string,
+	body interface{},
+) (*resty.Response, error) {
+	request := c.request()
+	if body != nil {
+		request.
+			SetHeader("content-type", "application/json").
+			SetBody(body)
+	}
+
+	r, err := request.Execute(method, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
+
+	return r, nil
+}
+
+func (c *GitlabClient) get(url string) (*resty.Response, error) {
+	return c.send(resty.MethodGet, url, nil)
+}
+
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+func getNextPageURL(link string) string {
+	m := linkNextRegexp.FindStringSubmatch(link)
+	if len(m) != 2 {
+		return ""
+	}
+
+	return m[1]
+}
+
+func (c *GitlabClient) getAll(
+	url string,
+	fn func(value gjson.Result),
+) error {
+	r, err := c.request().
+		SetQueryParam("per_page", "100").
+		Get(url)
+
+	for {
+		if err != nil {
+			return err
+		}
+		if r.IsError() {
+			return errors.New(string(r.Body()))
+		}
+
+		gjson.ParseBytes(r.Body()).ForEach(func(key, value gjson.Result) bool {
+			fn(value)
+			return true
+		})
+
+		next := getNextPageURL(r.Header().Get("Link"))
+		if next == "" {
+			return nil
+		}
+
+		r, err = c.request().Get(next)
+	}
+}
+
+func stateQueryParam(s preqClient.PullRequestState) string {
+	switch s {
+	case preqClient.PullRequestState_OPEN:
+		return "opened"
+	case preqClient.PullRequestState_MERGED:
+		return "merged"
+	case preqClient.PullRequestState_DECLINED:
+		return "closed"
+	}
+
+	return "all"
+}
+
+func parseState(value gjson.Result) preqClient.PullRequestState {
+	switch value.Get("state").String() {
+	case "merged":
+		return preqClient.PullRequestState_MERGED
+	case "closed":
+		return preqClient.PullRequestState_DECLINED
+	}
+
+	return preqClient.PullRequestState_OPEN
+}
+
+// isDraft reads the draft state, older GitLab versions only
+// report `work_in_progress`
+func isDraft(value gjson.Result) bool {
+	return value.Get("draft").Bool() || value.Get("work_in_progress").Bool()
+}
+
+func parseMergeRequest(value gjson.Result) *preqClient.PullRequest {
+	return &preqClient.PullRequest{
+		ID:           value.Get("iid").String(),
+		Title:        value.Get("title").String(),
+		Description:  value.Get("description").String(),
+		CommentCount: int(value.Get("user_notes_count").Int()),
+		User:         value.Get("author.username").String(),
+		URL:          value.Get("web_url").String(),
+		State:        parseState(value),
+		IsDraft:      isDraft(value),
+		Source: preqClient.PullRequestBranch{
+			Name: value.Get("source_branch").String(),
+			Hash: value.Get("sha").String(),
+		},
+		// The destination hash is only included when a single merge
+		// request is fetched
+		Destination: preqClient.PullRequestBranch{
+			Name: value.Get("target_branch").String(),
+			Hash: value.Get("diff_refs.base_sha").String(),
+		},
+		Created: value.Get("created_at").Time(),
+		Updated: value.Get("updated_at").Time(),
+	}
+}
+
+// filterQueryParams maps the filter to the merge request list parameters
+func filterQueryParams(f *preqClient.PullRequestFilter) map[string]string {
+	params := map[string]string{}
+	if f == nil {
+		return params
+	}
+
+	if f.Author != "" {
+		params["author_username"] = f.Author
+	}
+	if f.Reviewer != "" {
+		params["reviewer_username"] = f.Reviewer
+	}
+	if f.Source != "" {
+		params["source_branch"] = f.Source
+	}
+	if f.Destination != "" {
+		params["target_branch"] = f.Destination
+	}
+	if !f.UpdatedSince.IsZero() {
+		params["updated_after"] = f.UpdatedSince.UTC().Format(time.RFC3339)
+	}
+	if f.Search != "" {
+		params["search"] = f.Search
+	}
+
+	return params
+}
+
+func (c *GitlabClient) GetPullRequests(
+	o *preqClient.GetPullRequestsOptions,
+) (*preqClient.PullRequestList, error) {
+	request := c.request()
+
+	if o.State == preqClient.PullRequestState_SUPERSEDED {
+		return nil, preqClient.ErrUnsupportedState
+	}
+
+	url := o.Next
+	if url == "" {
+		url = c.mergeRequestsURL(o.Repository)
+		request.
+			SetQueryParam("state", stateQueryParam(o.State)).
+			SetQueryParam("per_page", "100").
+			SetQueryParams(filterQueryParams(o.Filter))
+	}
+
+	r, err := request.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
+
+	var prs preqClient.PullRequestList
+	parsed := gjson.ParseBytes(r.Body())
+	prs.PageLength = uint(parsed.Get("#").Uint())
+	prs.NextURL = getNextPageURL(r.Header().Get("Link"))
+	// GitLab omits the totals for large result sets
+	prs.Size = uint(gjson.Parse(r.Header().Get("X-Total")).Uint())
+	prs.Page = uint(gjson.Parse(r.Header().Get("X-Page")).Uint())
+	parsed.ForEach(func(key, value gjson.Result) bool {
+		prs.Values = append(prs.Values, parseMergeRequest(value))
+		return true
+	})
+
+	return &prs, nil
+}
+
+func (c *GitlabClient) getMergeRequest(
+	repo *preqClient.Repository,
+	id string,
+) (gjson.Result, error) {
+	r, err := c.get(c.mergeRequestURL(repo, id))
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	return gjson.ParseBytes(r.Body()), nil
+}
+
+func (c *GitlabClient) GetPullRequestInfo(
+	o *preqClient.ApproveOptions,
+) (*preqClient.PullRequest, error) {
+	value, err := c.getMergeRequest(o.Repository, o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMergeRequest(value), nil
+}
+
+func verifyCreatePullRequestOptions(
+	o *preqClient.CreatePullRequestOptions,
+) error {
+	if o.Source == "" {
+		return errors.New("missing source branch")
+	}
+
+	if o.Destination == "" {
+		return errors.New("missing destination branch")
+	}
+
+	return nil
+}
+
+func (c *GitlabClient) CreatePullRequest(
+	o *preqClient.CreatePullRequestOptions,
+) (*preqClient.PullRequest, error) {
+	err := verifyCreatePullRequestOptions(o)
+	if err != nil {
+		return nil, err
+	}
+
+	title := o.Title
+	if o.Draft {
+		title = setDraftPrefix(title, true)
+	}
+
+	reviewerIDs := []int64{}
+	for _, username := range o.WithoutAuthor() {
+		id, err := c.getUserID(username)
+		if err != nil {
+			return nil, err
+		}
+
+		reviewerIDs = append(reviewerIDs, id)
+	}
+
+	r, err := c.send(
+		resty.MethodPost,
+		c.mergeRequestsURL(o.Repository),
+		glMergeRequestOptions{
+			SourceBranch:       o.Source,
+			TargetBranch:       o.Destination,
+			Title:              title,
+			Description:        o.Description,
+			RemoveSourceBranch: o.CloseBranch,
+			ReviewerIDs:        reviewerIDs,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMergeRequest(gjson.ParseBytes(r.Body())), nil
+}
+
+// GitLab marks merge requests as drafts by the title prefix
+var draftPrefixRegexp = regexp.MustCompile(`(?i)^\s*(draft:|\[draft\]|\(draft\))\s*`)
+
+func setDraftPrefix(title string, draft bool) string {
+	title = draftPrefixRegexp.ReplaceAllString(title, "")
+	if draft {
+		return fmt.Sprintf("Draft: %s", title)
+	}
+
+	return title
+}
+
+func (c *GitlabClient) getUserID(username string) (int64, error) {
+	r, err := c.request().
+		SetQueryParam("username", username).
+		Get(fmt.Sprintf("%s/users", c.baseURL))
+	if err != nil {
+		return 0, err
+	}
+	if r.IsError() {
+		return 0, errors.New(string(r.Body()))
+	}
+
+	id := gjson.GetBytes(r.Body(), "0.id")
+	if !id.Exists() {
+		return 0, fmt.Errorf("unknown user %s", username)
+	}
+
+	return id.Int(), nil
+}
+
+func parseUser(value gjson.Result) *preqClient.User {
+	username := value.Get("username").String()
+	return &preqClient.User{
+		ID:       username,
+		Username: username,
+		Name:     value.Get("name").String(),
+	}
+}
+
+func (c *GitlabClient) GetCurrentUser() (*preqClient.User, error) {
+	r, err := c.get(fmt.Sprintf("%s/user", c.baseURL))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseUser(gjson.ParseBytes(r.Body())), nil
+}
+
+func (c *GitlabClient) SearchUsers(
+	o *preqClient.SearchUsersOptions,
+) ([]*preqClient.User, error) {
+	r, err := c.request().
+		SetQueryParam("search", o.Query).
+		Get(fmt.Sprintf("%s/users", c.baseURL))
+	if err != nil {
+		return nil, err
+	}
+	if r.IsError() {
+		return nil, errors.New(string(r.Body()))
+	}
+
+	users := []*preqClient.User{}
+	gjson.ParseBytes(r.Body()).ForEach(func(key, value gjson.Result) bool {
+		users = append(users, parseUser(value))
+		return true
+	})
+
+	return users, nil
+}
+
+func (c *GitlabClient) updateReviewerIDs(
+	current gjson.Result,
+	add []string,
+	remove []string,
+) ([]int64, error) {
+	ids := []int64{}
+	usernames := map[string]bool{}
+	current.ForEach(func(key, value gjson.Result) bool {
+		username := value.Get("username").String()
+		for _, v := range remove {
+			if v == username {
+				return true
+			}
+		}
+
+		usernames[username] = true
+		ids = append(ids, value.Get("id").Int())
+		return true
+	})
+
+	for _, username := range add {
+		if usernames[username] {
+			continue
+		}
+
+		id, err := c.getUserID(username)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (c *GitlabClient) UpdatePullRequest(
+	o *preqClient.UpdatePullRequestOptions,
+) (*preqClient.PullRequest, error) {
+	current, err := c.getMergeRequest(o.Repository, o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	options := glMergeRequestUpdateOptions{
+		Title:        o.Title,
+		Description:  o.Description,
+		TargetBranch: o.Destination,
+	}
+
+	// Changing the title would otherwise also change the draft state
+	draft := isDraft(current)
+	if o.Draft != nil {
+		draft = *o.Draft
+	}
+	if o.Title != nil || o.Draft != nil {
+		title := current.Get("title").String()
+		if o.Title != nil {
+			title = *o.Title
+		}
+
+		title = setDraftPrefix(title, draft)
+		options.Title = &title
+	}
+
+	if len(o.AddReviewers) > 0 || len(o.RemoveReviewers) > 0 {
+		ids, err := c.updateReviewerIDs(
+			current.Get("reviewers"),
+			o.AddReviewers,
+			o.RemoveReviewers,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		options.ReviewerIDs = &ids
+	}
+
+	r, err := c.send(
+		resty.MethodPut,
+		c.mergeRequestURL(o.Repository, o.ID),
+		options,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMergeRequest(gjson.ParseBytes(r.Body())), nil
+}
+
+func (c *GitlabClient) DeclinePullRequest(
+	o *preqClient.DeclinePullRequestOptions,
+) (*preqClient.PullRequest, error) {
+	r, err := c.send(
+		resty.MethodPut,
+		c.mergeRequestURL(o.Repository, o.ID),
+		glMergeRequestOptions{StateEvent: "close"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMergeRequest(gjson.ParseBytes(r.Body())), nil
+}
+
+func (c *GitlabClient) Merge(
+	o *preqClient.MergeOptions,
+) (*preqClient.PullRequest, error) {
+	options := glMergeOptions{}
+	switch o.Strategy {
+	case preqClient.MergeStrategy_SQUASH:
+		options.Squash = true
+		options.SquashCommitMessage = o.Message
+	case preqClient.MergeStrategy_FAST_FORWARD:
+		return nil, ErrUnsupportedMergeStrategy
+	default:
+		options.MergeCommitMessage = o.Message
+	}
+
+	r, err := c.send(
+		resty.MethodPut,
+		fmt.Sprintf("%s/merge", c.mergeRequestURL(o.Repository, o.ID)),
+		options,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMergeRequest(gjson.ParseBytes(r.Body())), nil
+}
+
+func (c *GitlabClient) Approve(
+	o *preqClient.ApproveOptions,
+) (*preqClient.PullRequest, error) {
+	_, err := c.send(
+		resty.MethodPost,
+		fmt.Sprintf("%s/approve", c.mergeRequestURL(o.Repository, o.ID)),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &preqClient.PullRequest{ID: o.ID}, nil
+}
+
+func (c *GitlabClient) Unapprove(
+	o *preqClient.UnapproveOptions,
+) (*preqClient.PullRequest, error) {
+	_, err := c.send(
+		resty.MethodPost,
+		fmt.Sprintf("%s/unapprove", c.mergeRequestURL(o.Repository, o.ID)),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &preqClient.PullRequest{ID: o.ID}, nil
+}
+
+func (c *GitlabClient) FillMiscInfoAsync(
+	repo *preqClient.Repository,
+	pr *preqClient.PullRequest,
+) error {
+	r, err := c.get(fmt.Sprintf("%s/approvals", c.mergeRequestURL(repo, pr.ID)))
+	if err != nil {
+		return err
+	}
+
+	pr.Approvals = []*preqClient.PullRequestApproval{}
+	gjson.GetBytes(r.Body(), "approved_by").ForEach(func(key, value gjson.Result) bool {
+		pr.Approvals = append(pr.Approvals, &preqClient.PullRequestApproval{
+			User: value.Get("user.username").String(),
+		})
+		return true
+	})
+
+	pr.ChangesRequests = []*preqClient.PullRequestChangesRequest{}
+	err = c.getAll(
+		fmt.Sprintf("%s/reviewers", c.mergeRequestURL(repo, pr.ID)),
+		func(value gjson.Result) {
+			if value.Get("state").String() != "requested_changes" {
+				return
+			}
+
+			pr.ChangesRequests = append(pr.ChangesRequests, &preqClient.PullRequestChangesRequest{
+				Created: value.Get("created_at").Time(),
+				User:    value.Get("user.username").String(),
+			})
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	info, err := c.getMergeRequest(repo, pr.ID)
+	if err != nil {
+		return err
+	}
+	pr.CommentCount = int(info.Get("user_notes_count").Int())
+
+	return nil
+}
+
+func (c *GitlabClient) isApprovedBy(
+	repo *preqClient.Repository,
+	id string,
+	user *preqClient.User,
+) (bool, error) {
+	r, err := c.get(fmt.Sprintf("%s/approvals", c.mergeRequestURL(repo, id)))
+	if err != nil {
+		return false, err
+	}
+
+	for _, value := range gjson.GetBytes(r.Body(), "approved_by").Array() {
+		if user.Matches(value.Get("user.username").String()) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetReviewRequests lists the open merge requests where the user is a
+// reviewer, the approvals cannot be filtered by the list so they are
+// checked for every merge request
+func (c *GitlabClient) GetReviewRequests(
+	o *preqClient.GetReviewRequestsOptions,
+) ([]*preqClient.PullRequest, error) {
+	user := o.User
+	if user == nil {
+		var err error
+		user, err = c.GetCurrentUser()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	prs := []*preqClient.PullRequest{}
+	err := c.getAll(
+		fmt.Sprintf(
+			"%s?state=opened&reviewer_username=%s",
+			c.mergeRequestsURL(o.Repository),
+			url.QueryEscape(user.Username),
+		),
+		func(value gjson.Result) {
+			prs = append(prs, parseMergeRequest(value))
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []*preqClient.PullRequest{}
+	for _, pr := range prs {
+		approved, err := c.isApprovedBy(o.Repository, pr.ID, user)
+		if err != nil {
+			return nil, err
+		}
+		if !approved {
+			values = append(values, pr)
+		}
+	}
+
+	return values, nil
+}
+
+// Comment IDs are in the `discussionID/noteID` form since replies are
+// added to a discussion and deletions are done by the note ID.
+func commentID(discussionID string, noteID string) string {
+	return fmt.Sprintf("%s/%s", discussionID, noteID)
+}
+
+func parseCommentID(id string) (string, string, error) {
+	v := strings.SplitN(id, "/", 2)
+	if len(v) != 2 || v[0] == "" || v[1] == "" {
+		return "", "", ErrInvalidCommentID
+	}
+
+	return v[0], v[1], nil
+}
+
+func parseNote(
+	discussionID string,
+	value gjson.Result,
+) *preqClient.PullRequestComment {
+	var typ preqClient.CommentType = preqClient.CommentTypeGlobal
+	position := value.Get("position")
+	if position.Exists() {
+		typ = preqClient.CommentTypeInline
+		if position.Get("position_type").String() == "file" {
+			typ = preqClient.CommentTypeFile
+		}
+	}
+
+	filePath := position.Get("new_path").String()
+	if filePath == "" {
+		filePath = position.Get("old_path").String()
+	}
+
+	// Unchanged lines have both line numbers set, these are placed by the
+	// new line number
+	afterLineNumber := uint(position.Get("new_line").Uint())
+	var beforeLineNumber uint
+	if afterLineNumber == 0 {
+		beforeLineNumber = uint(position.Get("old_line").Uint())
+	}
+
+	return &preqClient.PullRequestComment{
+		ID:               commentID(discussionID, value.Get("id").String()),
+		Type:             typ,
+		Content:          value.Get("body").String(),
+		Created:          value.Get("created_at").Time(),
+		Updated:          value.Get("updated_at").Time(),
+		User:             value.Get("author.username").String(),
+		BeforeLineNumber: beforeLineNumber,
+		AfterLineNumber:  afterLineNumber,
+		FilePath:         filePath,
+		CommitHash:       position.Get("head_sha").String(),
+		IsBeingStored:    false,
+		IsBeingDeleted:   false,
+	}
+}
+
+// parseDiscussion returns the notes of a discussion, the notes following
+// the first one are replies to it
+func parseDiscussion(value gjson.Result) []*preqClient.PullRequestComment {
+	discussionID := value.Get("id").String()
+
+	comments := []*preqClient.PullRequestComment{}
+	parentID := ""
+	value.Get("notes").ForEach(func(key, note gjson.Result) bool {
+		// System notes are events like pushes or label changes
+		if note.Get("system").Bool() {
+			return true
+		}
+
+		comment := parseNote(discussionID, note)
+		if parentID == "" {
+			parentID = comment.ID
+		} else {
+			comment.Type = preqClient.CommentTypeReply
+			comment.ParentID = parentID
+		}
+
+		comments = append(comments, comment)
+		return true
+	})
+
+	return comments
+}
+
+// writeFileDiff writes the changes of a file of the diffs endpoint in the
+// format of git diff, the API only returns the hunks. The diffs of binary
+// files are empty.
+func writeFileDiff(b *strings.Builder, value gjson.Result) {
+	oldPath := value.Get("old_path").String()
+	newPath := value.Get("new_path").String()
+	diff := value.Get("diff").String()
+	renamed := value.Get("renamed_file").Bool()
+
+	from, to := "a/"+oldPath, "b/"+newPath
+	fmt.Fprintf(b, "diff --git %s %s\n", from, to)
+
+	switch {
+	case value.Get("new_file").Bool():
+		from = "/dev/null"
+		fmt.Fprintf(b, "new file mode %s\n", value.Get("b_mode").String())
+	case value.Get("deleted_file").Bool():
+		to = "/dev/null"
+		fmt.Fprintf(b, "deleted file mode %s\n", value.Get("a_mode").String())
+	case value.Get("a_mode").String() != value.Get("b_mode").String():
+		fmt.Fprintf(b, "old mode %s\nnew mode %s\n", value.Get("a_mode").String(), value.Get("b_mode").String())
+	}
+
+	if renamed {
+		if diff == "" {
+			b.WriteString("similarity index 100%\n")
+		}
+		fmt.Fprintf(b, "rename from %s\nrename to %s\n", oldPath, newPath)
+	}
+
+	// The empty files, the changes of the mode or the name only and the diffs
+	// GitLab leaves out for being too large or collapsed have no content,
+	// only the header is written for them like git does for the empty files
+	switch {
+	case diff == "":
+		return
+	case strings.HasPrefix(diff, "Binary files"):
+		b.WriteString(diff)
+	default:
+		fmt.Fprintf(b, "--- %s\n+++ %s\n%s", from, to, diff)
+	}
+
+	if !strings.HasSuffix(diff, "\n") {
+		b.WriteString("\n")
+	}
+}
+
+func (c *GitlabClient) GetDiff(o *preqClient.GetDiffOptions) ([]byte, error) {
+	b := &strings.Builder{}
+	err := c.getAll(
+		fmt.Sprintf("%s/diffs", c.mergeRequestURL(o.Repository, o.ID)),
+		func(value gjson.Result) {
+			writeFileDiff(b, value)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(b.String()), nil
+}
+
+func (c *GitlabClient) GetComments(
+	o *preqClient.GetCommentsOptions,
+) ([]*preqClient.PullRequestComment, error) {
+	comments := []*preqClient.PullRequestComment{}
+	err := c.getAll(
+		fmt.Sprintf("%s/discussions", c.mergeRequestURL(o.Repository, o.ID)),
+		func(value gjson.Result) {
+			comments = append(comments, parseDiscussion(value)...)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// setLinePosition positions the comment on the line, the unchanged lines
+// need their numbers in both versions of the file
+func setLinePosition(p *glPosition, ref *preqClient.CreateCommentOptionsLineRef) {
+	p.PositionType = "text"
+	switch {
+	case ref.Context:
+		p.OldLine = ref.OriginalLineNumber
+		p.NewLine = ref.LineNumber
+	case ref.Type == preqClient.OriginalLineNumber:
+		p.OldLine = ref.LineNumber
+	default:
+		p.NewLine = ref.LineNumber
+	}
+}
+
+func (c *GitlabClient) CreateComment(
+	o *preqClient.CreateCommentOptions,
+) (*preqClient.PullRequestComment, error) {
+	discussionsURL := fmt.Sprintf(
+		"%s/discussions",
+		c.mergeRequestURL(o.Repository, o.ID),
+	)
+
+	if o.ParentRef != nil {
+		discussionID, _, err := parseCommentID(o.ParentRef.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := c.send(
+			resty.MethodPost,
+			fmt.Sprintf("%s/%s/notes", discussionsURL, discussionID),
+			glNoteOptions{Body: o.Content},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		comment := parseNote(discussionID, gjson.ParseBytes(r.Body()))
+		comment.Type = preqClient.CommentTypeReply
+		comment.ParentID = o.ParentRef.ID
+
+		return comment, nil
+	}
+
+	options := glNoteOptions{Body: o.Content}
+	if o.FilePath != "" {
+		// Positions are relative to the merge request's latest diff
+		mr, err := c.getMergeRequest(o.Repository, o.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		options.Position = &glPosition{
+			PositionType: "file",
+			BaseSHA:      mr.Get("diff_refs.base_sha").String(),
+			StartSHA:     mr.Get("diff_refs.start_sha").String(),
+			HeadSHA:      mr.Get("diff_refs.head_sha").String(),
+			OldPath:      o.FilePath,
+			NewPath:      o.FilePath,
+		}
+
+		if o.LineRef != nil {
+			setLinePosition(options.Position, o.LineRef)
+		}
+	}
+
+	r, err := c.send(resty.MethodPost, discussionsURL, options)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := parseDiscussion(gjson.ParseBytes(r.Body()))
+	if len(comments) == 0 {
+		return nil, errors.New("created discussion has no notes")
+	}
+
+	return comments[0], nil
+}
+
+func (c *GitlabClient) DeleteComment(
+	o *preqClient.DeleteCommentOptions,
+) error {
+	_, noteID, err := parseCommentID(o.CommentID)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.send(
+		resty.MethodDelete,
+		fmt.Sprintf("%s/notes/%s", c.mergeRequestURL(o.Repository, o.ID), noteID),
+		nil,
+	)
+
+	return err
+}
@@ -0,0 +1,203 @@
+package gitlab
+
+import (
+	preqClient "preq/internal/pkg/client"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func Test_parseCommentID(t *testing.T) {
+	t.Run("splits the discussion and note IDs", func(t *testing.T) {
+		discussionID, noteID, err := parseCommentID("abc/12")
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", discussionID)
+		assert.Equal(t, "12", noteID)
+	})
+
+	t.Run("fails without a note ID", func(t *testing.T) {
+		_, _, err := parseCommentID("12")
+		assert.ErrorIs(t, err, ErrInvalidCommentID)
+	})
+}
+
+func Test_parseDiscussion(t *testing.T) {
+	t.Run("skips system notes and marks replies", func(t *testing.T) {
+		v := parseDiscussion(gjson.Parse(`{
+			"id": "abc",
+			"notes": [
+				{"id": 1, "body": "first", "system": false, "author": {"username": "a"}},
+				{"id": 2, "body": "pushed", "system": true},
+				{"id": 3, "body": "reply", "system": false, "author": {"username": "b"}}
+			]
+		}`))
+		assert.Equal(t, 2, len(v))
+		assert.Equal(t, "abc/1", v[0].ID)
+		assert.Equal(t, preqClient.CommentType(preqClient.CommentTypeGlobal), v[0].Type)
+		assert.Equal(t, "abc/3", v[1].ID)
+		assert.Equal(t, preqClient.CommentType(preqClient.CommentTypeReply), v[1].Type)
+		assert.Equal(t, "abc/1", v[1].ParentID)
+	})
+
+	t.Run("places inline comments on unchanged lines by the new line", func(t *testing.T) {
+		v := parseDiscussion(gjson.Parse(`{
+			"id": "abc",
+			"notes": [{
+				"id": 1,
+				"position": {
+					"position_type": "text",
+					"head_sha": "deadbeef",
+					"old_path": "a.go",
+					"new_path": "a.go",
+					"old_line": 4,
+					"new_line": 5
+				}
+			}]
+		}`))
+		assert.Equal(t, 1, len(v))
+		assert.Equal(t, preqClient.CommentType(preqClient.CommentTypeInline), v[0].Type)
+		assert.Equal(t, uint(0), v[0].BeforeLineNumber)
+		assert.Equal(t, uint(5), v[0].AfterLineNumber)
+		assert.Equal(t, "a.go", v[0].FilePath)
+		assert.Equal(t, "deadbeef", v[0].CommitHash)
+	})
+}
+
+func Test_parseState(t *testing.T) {
+	t.Run("closed merge requests are declined", func(t *testing.T) {
+		v := parseState(gjson.Parse(`{"state": "closed"}`))
+		assert.Equal(t, preqClient.PullRequestState(preqClient.PullRequestState_DECLINED), v)
+	})
+
+	t.Run("locked merge requests are open", func(t *testing.T) {
+		v := parseState(gjson.Parse(`{"state": "locked"}`))
+		assert.Equal(t, preqClient.PullRequestState(preqClient.PullRequestState_OPEN), v)
+	})
+}
+
+func Test_setDraftPrefix(t *testing.T) {
+	t.Run("adds the draft prefix", func(t *testing.T) {
+		assert.Equal(t, "Draft: title", setDraftPrefix("title", true))
+	})
+
+	t.Run("does not duplicate an existing prefix", func(t *testing.T) {
+		assert.Equal(t, "Draft: title", setDraftPrefix("[Draft] title", true))
+	})
+
+	t.Run("removes the draft prefix", func(t *testing.T) {
+		assert.Equal(t, "title", setDraftPrefix("Draft: title", false))
+	})
+}
+
+func Test_isDraft(t *testing.T) {
+	t.Run("reads the draft flag", func(t *testing.T) {
+		assert.True(t, isDraft(gjson.Parse(`{"draft": true}`)))
+	})
+
+	t.Run("falls back to the work in progress flag", func(t *testing.T) {
+		assert.True(t, isDraft(gjson.Parse(`{"work_in_progress": true}`)))
+		assert.False(t, isDraft(gjson.Parse(`{}`)))
+	})
+}
+
+func Test_filterQueryParams(t *testing.T) {
+	assert.Empty(t, filterQueryParams(nil))
+	assert.Equal(t, map[string]string{
+		"author_username":   "alice",
+		"reviewer_username": "bob",
+		"source_branch":     "fix",
+		"target_branch":     "main",
+		"updated_after":     "2023-03-01T00:00:00Z",
+		"search":            "parser",
+	}, filterQueryParams(&preqClient.PullRequestFilter{
+		Author:       "alice",
+		Reviewer:     "bob",
+		Source:       "fix",
+		Destination:  "main",
+		UpdatedSince: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+		Search:       "parser",
+	}))
+}
+
+func Test_writeFileDiff(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "modified",
+			value: `{"old_path": "a.txt", "new_path": "a.txt", "a_mode": "100644", "b_mode": "100644", "diff": "@@ -1 +1 @@\n-a\n+b\n"}`,
+			want:  "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-a\n+b\n",
+		},
+		{
+			name:  "added",
+			value: `{"old_path": "a.txt", "new_path": "a.txt", "a_mode": "0", "b_mode": "100644", "new_file": true, "diff": "@@ -0,0 +1 @@\n+b\n"}`,
+			want:  "diff --git a/a.txt b/a.txt\nnew file mode 100644\n--- /dev/null\n+++ b/a.txt\n@@ -0,0 +1 @@\n+b\n",
+		},
+		{
+			name:  "renamed",
+			value: `{"old_path": "a.txt", "new_path": "b.txt", "a_mode": "100644", "b_mode": "100644", "renamed_file": true, "diff": ""}`,
+			want:  "diff --git a/a.txt b/b.txt\nsimilarity index 100%\nrename from a.txt\nrename to b.txt\n",
+		},
+		{
+			name:  "empty deleted file",
+			value: `{"old_path": "a.txt", "new_path": "a.txt", "a_mode": "100644", "b_mode": "0", "deleted_file": true, "diff": ""}`,
+			want:  "diff --git a/a.txt b/a.txt\ndeleted file mode 100644\n",
+		},
+		{
+			name:  "too large",
+			value: `{"old_path": "a.txt", "new_path": "a.txt", "a_mode": "100644", "b_mode": "100644", "too_large": true, "diff": ""}`,
+			want:  "diff --git a/a.txt b/a.txt\n",
+		},
+		{
+			name:  "binary",
+			value: `{"old_path": "a.bin", "new_path": "a.bin", "a_mode": "100644", "b_mode": "100644", "diff": "Binary files a/a.bin and b/a.bin differ\n"}`,
+			want:  "diff --git a/a.bin b/a.bin\nBinary files a/a.bin and b/a.bin differ\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &strings.Builder{}
+			writeFileDiff(b, gjson.Parse(tt.value))
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func Test_setLinePosition(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		ref  *preqClient.CreateCommentOptionsLineRef
+		want glPosition
+	}{
+		{
+			name: "removed line",
+			ref:  &preqClient.CreateCommentOptionsLineRef{LineNumber: 3, Type: preqClient.OriginalLineNumber},
+			want: glPosition{PositionType: "text", OldLine: 3},
+		},
+		{
+			name: "added line",
+			ref:  &preqClient.CreateCommentOptionsLineRef{LineNumber: 4, Type: preqClient.NewLineNumber},
+			want: glPosition{PositionType: "text", NewLine: 4},
+		},
+		{
+			name: "unchanged line",
+			ref: &preqClient.CreateCommentOptionsLineRef{
+				LineNumber:         5,
+				Type:               preqClient.NewLineNumber,
+				Context:            true,
+				OriginalLineNumber: 3,
+			},
+			want: glPosition{PositionType: "text", OldLine: 3, NewLine: 5},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &glPosition{PositionType: "file"}
+			setLinePosition(p, tt.ref)
+			assert.Equal(t, tt.want, *p)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package gitlab
+
+type glError struct {
+	Message interface{} `json:"message"`
+	Error   string      `json:"error"`
+}
+
+type glMergeRequestOptions struct {
+	SourceBranch       string  `json:"source_branch,omitempty"`
+	TargetBranch       string  `json:"target_branch,omitempty"`
+	Title              string  `json:"title,omitempty"`
+	Description        string  `json:"description,omitempty"`
+	RemoveSourceBranch bool    `json:"remove_source_branch,omitempty"`
+	StateEvent         string  `json:"state_event,omitempty"`
+	ReviewerIDs        []int64 `json:"reviewer_ids,omitempty"`
+}
+
+type glMergeRequestUpdateOptions struct {
+	Title        *string  `json:"title,omitempty"`
+	Description  *string  `json:"description,omitempty"`
+	TargetBranch *string  `json:"target_branch,omitempty"`
+	ReviewerIDs  *[]int64 `json:"reviewer_ids,omitempty"`
+}
+
+type glMergeOptions struct {
+	MergeCommitMessage  string `json:"merge_commit_message,omitempty"`
+	Squash              bool   `json:"squash,omitempty"`
+	SquashCommitMessage string `json:"squash_commit_message,omitempty"`
+}
+
+type glPosition struct {
+	PositionType string `json:"position_type"`
+	BaseSHA      string `json:"base_sha"`
+	StartSHA     string `json:"start_sha"`
+	HeadSHA      string `json:"head_sha"`
+	OldPath      string `json:"old_path,omitempty"`
+	NewPath      string `json:"new_path,omitempty"`
+	OldLine      int    `json:"old_line,omitempty"`
+	NewLine      int    `json:"new_line,omitempty"`
+}
+
+type glNoteOptions struct {
+	Body     string      `json:"body"`
+	Position *glPosition `json:"position,omitempty"`
+}
@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"preq/internal/gitutils"
+	"preq/internal/persistance"
+
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+)
+
+// CheckoutModal checks out the pull request in its local repository, it
+// asks before stashing the uncommitted changes
+type CheckoutModal struct {
+	*tview.Modal
+	pr *PullRequest
+}
+
+func NewCheckoutModal() *CheckoutModal {
+	m := &CheckoutModal{Modal: tview.NewModal()}
+
+	m.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		switch buttonLabel {
+		case "Stash":
+			m.Start(m.pr, true)
+		default:
+			eventBus.Publish("CheckoutModal:CloseRequested", nil)
+		}
+	})
+
+	return m
+}
+
+func (m *CheckoutModal) setState(text string, buttons ...string) {
+	m.ClearButtons().
+		SetText(text).
+		AddButtons(buttons)
+}
+
+// Start checks out the pull request in the background
+func (m *CheckoutModal) Start(pr *PullRequest, stash bool) {
+	m.pr = pr
+	m.setState(fmt.Sprintf("Checking out pull request #%s...", pr.PullRequest.ID))
+
+	go func() {
+		result, err := checkoutPullRequest(pr, stash)
+		app.QueueUpdateDraw(func() {
+			if errors.Is(err, gitutils.ErrDirtyWorktree) {
+				m.setState(
+					"The worktree has uncommitted changes, stash them and check out the pull request?",
+					"Cancel",
+					"Stash",
+				)
+				return
+			}
+
+			if err != nil {
+				log.Error().Err(err).Msgf("failed to check out pull request #%s", pr.PullRequest.ID)
+				m.setState(fmt.Sprintf("Check out failed: %s", err), "Close")
+				return
+			}
+
+			text := fmt.Sprintf("Switched to branch '%s'", result.Branch)
+			if result.Stashed {
+				text += ", the uncommitted changes are stashed"
+			}
+			m.setState(text, "Close")
+		})
+	}()
+}
+
+func checkoutPullRequest(pr *PullRequest, stash bool) (*gitutils.CheckoutResult, error) {
+	info, err := persistance.GetDefault().GetInfo(
+		pr.Repository.Name,
+		string(pr.Repository.Provider),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if info.Path == "" {
+		return nil, fmt.Errorf("no local clone of %s is known", pr.Repository.Name)
+	}
+
+	return gitutils.CheckoutPullRequest(
+		info.Path,
+		pr.Repository,
+		pr.PullRequest,
+		&gitutils.CheckoutOptions{Stash: stash},
+	)
+}
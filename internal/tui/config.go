@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"preq/internal/gitutils"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/spf13/viper"
@@ -37,6 +38,9 @@ func initIconsMap(config *viper.Viper) map[string]string {
 		"OpenDirectory":    "📂",
 		"ClosedDirectory":  "📁",
 		"Working":          "⏳",
+		"MyPullRequest":    "★",
+		"MyApproval":       "✓",
+		"Draft":            "Draft",
 	}
 
 	if config.GetBool("general.useNerdFontIcons") {
@@ -57,6 +61,9 @@ func initIconsMap(config *viper.Viper) map[string]string {
 			"OpenDirectory":    "󰝰",
 			"ClosedDirectory":  "󰉋",
 			"Working":          "",
+			"MyPullRequest":    "",
+			"MyApproval":       "",
+			"Draft":            "",
 		}
 
 		for k := range nerdIconsMaps {
@@ -73,3 +80,17 @@ func initIconsMap(config *viper.Viper) map[string]string {
 
 	return iconsMap
 }
+
+func initDiffOptions(config *viper.Viper) *gitutils.DiffOptions {
+	o := gitutils.DefaultDiffOptions()
+	if config == nil {
+		return o
+	}
+
+	o.IgnoreWhitespace = config.GetBool("general.ignoreWhitespace")
+	if config.IsSet("general.diffContextLines") {
+		o.ContextLines = config.GetInt("general.diffContextLines")
+	}
+
+	return o
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"preq/internal/gitutils"
 	"preq/internal/pkg/client"
 	"time"
 
@@ -28,12 +29,16 @@ type DiffLineType int
 const (
 	DiffLineTypeAdded DiffLineType = iota
 	DiffLineTypeRemoved
+	DiffLineTypeContext
 )
 
 type diffLine struct {
 	FilePath   string
 	LineNumber int
 	Type       DiffLineType
+	// OriginalLineNumber is the number of an unchanged line in the original
+	// version of the file
+	OriginalLineNumber int
 }
 
 type DiffFileType int
@@ -51,11 +56,13 @@ type detailsPage struct {
 	reviewPanel *ReviewPanel
 	changes     []byte
 	commentsMap map[string]map[string][]*client.PullRequestComment
+	pullRequest *PullRequest
+	diffOptions *gitutils.DiffOptions
 }
 
 func CommentLineNumberTypeToDiffLineType(d DiffLineType) client.CommentLineNumberType {
 	var t client.CommentLineNumberType = client.OriginalLineNumber
-	if d == DiffLineTypeAdded {
+	if d == DiffLineTypeAdded || d == DiffLineTypeContext {
 		t = client.NewLineNumber
 	}
 
@@ -135,6 +142,9 @@ func newDetailsPage() *detailsPage {
 			case 'q':
 				eventBus.Publish("detailsPage:close", nil)
 				return nil
+			case 'w':
+				eventBus.Publish("DetailsPage:WhitespaceToggled", nil)
+				return nil
 			}
 
 			return event
@@ -200,8 +210,10 @@ func newDetailsPage() *detailsPage {
 					Content:    content,
 					FilePath:   d.FilePath,
 					LineRef: &client.CreateCommentOptionsLineRef{
-						LineNumber: d.LineNumber,
-						Type:       CommentLineNumberTypeToDiffLineType(d.Type),
+						LineNumber:         d.LineNumber,
+						Type:               CommentLineNumberTypeToDiffLineType(d.Type),
+						Context:            d.Type == DiffLineTypeContext,
+						OriginalLineNumber: d.OriginalLineNumber,
 					},
 				}
 			}
@@ -233,6 +245,9 @@ func newDetailsPage() *detailsPage {
 			} else {
 				afterLineNumber = options.LineRef.LineNumber
 			}
+			if options.LineRef.Context {
+				beforeLineNumber = options.LineRef.OriginalLineNumber
+			}
 		}
 
 		tempComment := &client.PullRequestComment{
@@ -316,21 +331,75 @@ func newDetailsPage() *detailsPage {
 		reviewPanel.prerenderContent(fileDiff.DiffId)
 	})
 
-	return &detailsPage{
+	dp := &detailsPage{
 		Grid:        grid,
 		fileTree:    fileTree,
 		reviewPanel: reviewPanel,
+		diffOptions: gitutils.DefaultDiffOptions(),
 	}
+
+	eventBus.Subscribe("DetailsPage:WhitespaceToggled", func(_ interface{}) {
+		dp.toggleWhitespace()
+	})
+
+	return dp
 }
 
-func (dp *detailsPage) SetData(pr *PullRequest) error {
-	dp.fileTree.Clear()
-	dp.reviewPanel.Clear()
+// toggleWhitespace shows or hides the whitespace changes, the diff of the
+// pull request is computed again
+func (dp *detailsPage) toggleWhitespace() {
+	if dp.pullRequest == nil || dp.pullRequest.GitUtil == nil {
+		return
+	}
+
+	dp.diffOptions.IgnoreWhitespace = !dp.diffOptions.IgnoreWhitespace
+	err := dp.SetData(dp.pullRequest)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to compute the diff")
+		eventBus.Publish("ErrorModal:RequestOpen", err)
+		return
+	}
 
-	changes, err := pr.GitUtil.GetDiffPatch(
+	app.SetFocus(dp.fileTree)
+}
+
+// getDiff computes the diff in the local clone, the provider's diff is used
+// for the repositories without one
+func (dp *detailsPage) getDiff(pr *PullRequest) ([]byte, error) {
+	if pr.GitUtil == nil {
+		return pr.Client.GetDiff(&client.GetDiffOptions{
+			Repository: pr.Repository,
+			ID:         pr.PullRequest.ID,
+		})
+	}
+
+	return pr.GitUtil.GetDiffPatch(
 		pr.PullRequest.Destination.Hash,
 		pr.PullRequest.Source.Hash,
+		dp.diffOptions,
 	)
+}
+
+func (dp *detailsPage) title() string {
+	// The whitespace changes cannot be hidden in the provider's diff
+	if dp.pullRequest.GitUtil == nil {
+		return "Review (no local clone, the diff is from the provider)"
+	}
+
+	if dp.diffOptions.IgnoreWhitespace {
+		return "Review (ignoring whitespace, w to show)"
+	}
+
+	return "Review (w to ignore whitespace)"
+}
+
+func (dp *detailsPage) SetData(pr *PullRequest) error {
+	dp.fileTree.Clear()
+	dp.reviewPanel.Clear()
+	dp.pullRequest = pr
+	dp.SetTitle(dp.title())
+
+	changes, err := dp.getDiff(pr)
 	if err != nil {
 		return err
 	}
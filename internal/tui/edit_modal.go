@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"preq/internal/clientutils"
+	"preq/internal/pkg/client"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+)
+
+type EditModal struct {
+	*tview.Flex
+	form *tview.Form
+	pr   *PullRequest
+}
+
+// splitList splits a comma separated input field value, ignoring empty items
+func splitList(s string) []string {
+	items := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			items = append(items, v)
+		}
+	}
+
+	return items
+}
+
+// SetData fills the form with the current values of the pull request
+func (m *EditModal) SetData(pr *PullRequest) {
+	m.pr = pr
+	m.form.Clear(true)
+	m.form.
+		AddInputField("Title", pr.PullRequest.Title, 0, nil, nil).
+		AddTextArea("Description", pr.PullRequest.Description, 0, 6, 0, nil).
+		AddInputField("Destination", pr.PullRequest.Destination.Name, 0, nil, nil).
+		AddInputField("Add reviewers", "", 0, nil, nil).
+		AddInputField("Remove reviewers", "", 0, nil, nil).
+		AddCheckbox("Draft", pr.PullRequest.IsDraft, nil).
+		AddButton("Save", m.save).
+		AddButton("Cancel", func() {
+			eventBus.Publish("EditModal:CloseRequested", nil)
+		})
+	m.form.SetFocus(0)
+}
+
+func (m *EditModal) inputText(label string) string {
+	return m.form.GetFormItemByLabel(label).(*tview.InputField).GetText()
+}
+
+// options builds the update options only from the changed form fields
+func (m *EditModal) options() *client.UpdatePullRequestOptions {
+	pr := m.pr.PullRequest
+	o := &client.UpdatePullRequestOptions{
+		Repository:      m.pr.Repository,
+		ID:              pr.ID,
+		AddReviewers:    splitList(m.inputText("Add reviewers")),
+		RemoveReviewers: splitList(m.inputText("Remove reviewers")),
+	}
+
+	if title := m.inputText("Title"); title != pr.Title && title != "" {
+		o.Title = &title
+	}
+
+	description := m.form.GetFormItemByLabel("Description").(*tview.TextArea).GetText()
+	if description != pr.Description {
+		o.Description = &description
+	}
+
+	if dest := m.inputText("Destination"); dest != pr.Destination.Name && dest != "" {
+		o.Destination = &dest
+	}
+
+	if draft := m.form.GetFormItemByLabel("Draft").(*tview.Checkbox).IsChecked(); draft != pr.IsDraft {
+		o.Draft = &draft
+	}
+
+	return o
+}
+
+func (m *EditModal) save() {
+	row, o := m.pr, m.options()
+	eventBus.Publish("EditModal:CloseRequested", nil)
+
+	go func() {
+		pr, err := updatePullRequest(row.Client, o)
+		if err != nil {
+			log.Error().Err(err).Msgf("failed to update pull request %s", o.ID)
+			app.QueueUpdateDraw(func() {
+				eventBus.Publish("ErrorModal:RequestOpen", err)
+			})
+			return
+		}
+
+		app.QueueUpdateDraw(func() {
+			row.PullRequest.Title = pr.Title
+			row.PullRequest.Description = pr.Description
+			row.PullRequest.Destination = pr.Destination
+			row.PullRequest.IsDraft = pr.IsDraft
+			redraw()
+		})
+	}()
+}
+
+// updatePullRequest resolves the names of the added and removed reviewers to
+// the provider's identifiers before updating the pull request
+func updatePullRequest(
+	c client.Client,
+	o *client.UpdatePullRequestOptions,
+) (*client.PullRequest, error) {
+	var err error
+	o.AddReviewers, err = clientutils.ResolveReviewers(c, o.Repository, o.AddReviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	o.RemoveReviewers, err = clientutils.ResolveReviewers(c, o.Repository, o.RemoveReviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.UpdatePullRequest(o)
+}
+
+func NewEditModal() *EditModal {
+	modal := func(p tview.Primitive, width, height int) *tview.Flex {
+		return tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(
+				tview.NewFlex().SetDirection(tview.FlexRow).
+					AddItem(nil, 0, 1, false).
+					AddItem(p, height, 1, true).
+					AddItem(nil, 0, 1, false),
+				width, 1, true,
+			).
+			AddItem(nil, 0, 1, false)
+	}
+
+	form := tview.NewForm()
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			eventBus.Publish("EditModal:CloseRequested", nil)
+			return nil
+		}
+
+		return event
+	})
+
+	form.SetTitle("Edit pull request").
+		SetBorder(true)
+
+	return &EditModal{
+		Flex: modal(form, 80, 20),
+		form: form,
+	}
+}
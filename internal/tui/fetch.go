@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"context"
+	"preq/internal/gitutils"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// fetchConcurrency limits the number of repositories fetched at once
+const fetchConcurrency = 4
+
+// fetchRepositories fetches the local clones of the repositories in the
+// background, so the details pages find the commits of the pull requests.
+// The failures are only logged.
+func fetchRepositories(ctx context.Context, data []*tableRepoData) {
+	sem := make(chan struct{}, fetchConcurrency)
+	wg := sync.WaitGroup{}
+
+	for _, d := range data {
+		if d.Path == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(d *tableRepoData) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			err := gitutils.FetchRepository(ctx, d.Path, d.Repository)
+			if err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msgf("failed to fetch %s", d.Repository.Name)
+				return
+			}
+
+			log.Debug().Msgf("fetched %s", d.Repository.Name)
+		}(d)
+	}
+
+	wg.Wait()
+}
@@ -2,129 +2,71 @@ package tui
 
 import (
 	"context"
-	"io"
-	"os/exec"
+	"fmt"
+	"preq/internal/gitutils"
 
-	"github.com/creack/pty"
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/rs/zerolog/log"
 )
 
+// GitFetchModal fetches the missing commits of a pull request, the details
+// page is opened once they are fetched. Git does not prompt for credentials,
+// the fetch fails instead.
 type GitFetchModal struct {
-	*tview.Grid
-	input  *tview.InputField
-	output *tview.TextView
+	*tview.Modal
+	cancel context.CancelFunc
 }
 
-func (m *GitFetchModal) StartGitFetch(path string) {
-	m.output.SetText("")
-	m.input.SetText("")
-
-	cmd := exec.Command("git", "fetch")
-	cmd.Dir = path
-
-	// Create a pipe for capturing the terminal output
-	pr, pw := io.Pipe()
-
-	// Start the command
-	ptmx, err := pty.Start(cmd)
-	if err != nil {
-		panic(err)
-	}
+func NewGitFetchModal() *GitFetchModal {
+	m := &GitFetchModal{Modal: tview.NewModal()}
 
-	cleanup := func() {
-		// FIXME: Handle errors
-		err := ptmx.Close()
-		if err != nil {
-			log.Error().Err(err).Msg("Error while closing pty")
+	m.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if m.cancel != nil {
+			m.cancel()
 		}
+		eventBus.Publish("GitFetchModal:RequestClose", nil)
+	})
 
-		err = pw.Close()
-		if err != nil {
-			log.Error().Err(err).Msg("Error while closing fetch pipe writer")
-		}
+	return m
+}
 
-		err = pr.Close()
-		if err != nil {
-			log.Error().Err(err).Msg("Error while closing fetch pipe reader")
-		}
+func (m *GitFetchModal) setState(text string, buttons ...string) {
+	m.ClearButtons().
+		SetText(text).
+		AddButtons(buttons)
+}
+
+// StartGitFetch fetches the pull request in the repository at path in the
+// background, a running fetch is cancelled
+func (m *GitFetchModal) StartGitFetch(path string, pr *PullRequest) {
+	if m.cancel != nil {
+		m.cancel()
 	}
 
-	defer cleanup()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.setState(
+		fmt.Sprintf("Fetching the commits of pull request #%s...", pr.PullRequest.ID),
+		"Cancel",
+	)
+
+	go func() {
+		defer cancel()
 
-	// TODO: Is this how you use context?
-	ctx, cancelCtx := context.WithCancel(context.TODO())
-	m.input.
-		SetDoneFunc(func(key tcell.Key) {
-			if key == tcell.KeyEnter {
-				ptmx.WriteString(m.input.GetText() + "\n")
-				m.input.SetText("")
-			} else if key == tcell.KeyEscape {
-				cancelCtx()
+		err := gitutils.FetchPullRequest(ctx, path, pr.Repository, pr.PullRequest)
+		app.QueueUpdateDraw(func() {
+			// Cancelled or replaced by another fetch
+			if ctx.Err() != nil {
+				return
 			}
-		})
 
-	go func() {
-		output := ""
-		bfr := make([]byte, 128)
-		for {
-			n, err := pr.Read(bfr)
 			if err != nil {
-				log.Error().Err(err)
-				break
+				log.Error().Err(err).Msgf("failed to fetch pull request #%s", pr.PullRequest.ID)
+				m.setState(fmt.Sprintf("Fetching failed: %s", err), "Close")
+				return
 			}
 
-			output += string(bfr[:n])
-			m.output.SetText(output)
-		}
-	}()
-
-	go func() {
-		_, err = io.Copy(pw, ptmx)
-		if err != nil {
-			log.Error().Err(err).Msg("Error while coping from pty")
-		}
-
-		cancelCtx()
-	}()
-
-	select {
-	case <-ctx.Done():
-		// wait
-	}
-
-	eventBus.Publish("GitFetchModal:RequestClose", nil)
-}
-
-func NewGitFetchModal() *GitFetchModal {
-	outputView := tview.NewTextView().
-		SetWrap(true).
-		SetChangedFunc(func() {
-			app.Draw()
+			eventBus.Publish("GitFetchModal:Fetched", pr)
 		})
-
-	inputField := tview.NewInputField().
-		SetFieldBackgroundColor(tcell.ColorBlack).
-		SetMaskCharacter('*')
-	inputField.SetBorder(true)
-
-	f := tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(outputView, 0, 1, false).
-		AddItem(inputField, 3, 0, true)
-	f.SetTitle(" Git fetch ").SetBorder(true)
-
-	grid := tview.NewGrid().
-		SetColumns(0, 80, 0).
-		SetRows(0, 15, 0).
-		AddItem(f, 1, 1, 1, 1, 0, 0, true)
-
-	return &GitFetchModal{
-		Grid:   grid,
-		input:  inputField,
-		output: outputView,
-	}
+	}()
 }
-
-var gitFetchModal = NewGitFetchModal()
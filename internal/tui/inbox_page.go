@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"fmt"
+	"preq/internal/cli/utils"
+	"preq/internal/pkg/client"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+)
+
+// inboxRow is a pull request waiting for the review of the current user
+type inboxRow struct {
+	pullRequest *client.PullRequest
+	data        *tableRepoData
+}
+
+// InboxPage lists the review requests of the current user across all the
+// repositories, the oldest first
+type InboxPage struct {
+	*tview.Table
+	rows      []*inboxRow
+	isLoading bool
+	failed    int
+	// generation discards the results of a previous load
+	generation int
+}
+
+var inboxHeaders = []string{"AGE", "REPO", "#", "TITLE", "AUTHOR"}
+
+func NewInboxPage() *InboxPage {
+	p := &InboxPage{
+		Table: tview.NewTable().
+			SetFixed(1, 0).
+			SetSelectable(true, false),
+	}
+
+	p.SetBorder(true)
+
+	p.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Rune() == 'q' {
+			eventBus.Publish("InboxPage:CloseRequested", nil)
+			return nil
+		}
+
+		switch event.Key() {
+		case tcell.KeyEnter:
+			if pr := p.GetSelectedPullRequest(); pr != nil {
+				eventBus.Publish("InboxPage:CloseRequested", nil)
+				eventBus.Publish("detailsPage:open", pr)
+			}
+			return nil
+		case tcell.KeyCtrlO:
+			if row := p.selectedRow(); row != nil {
+				eventBus.Publish("BrowserUrlOpen", row.pullRequest.URL)
+			}
+			return nil
+		}
+
+		if event.Rune() == 'R' {
+			p.Load(tableData)
+			return nil
+		}
+
+		return event
+	})
+
+	p.redraw()
+
+	return p
+}
+
+// Load requests the review requests of every repository concurrently, the
+// page is redrawn once all of them responded
+func (p *InboxPage) Load(data []*tableRepoData) {
+	p.generation++
+	generation := p.generation
+	p.isLoading = true
+	p.rows = []*inboxRow{}
+	p.failed = 0
+	p.redraw()
+
+	go func() {
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			rows   = []*inboxRow{}
+			failed = 0
+		)
+		for _, d := range data {
+			wg.Add(1)
+			go func(d *tableRepoData) {
+				defer wg.Done()
+
+				prs, err := d.Client.GetReviewRequests(&client.GetReviewRequestsOptions{
+					Repository: d.Repository,
+				})
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					log.Error().Err(err).Msgf("failed to load the review requests of %s", d.Repository.Name)
+					failed++
+					return
+				}
+
+				for _, pr := range prs {
+					rows = append(rows, &inboxRow{pullRequest: pr, data: d})
+				}
+			}(d)
+		}
+		wg.Wait()
+
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].pullRequest.Created.Before(rows[j].pullRequest.Created)
+		})
+
+		app.QueueUpdateDraw(func() {
+			if generation != p.generation {
+				return
+			}
+
+			p.rows = rows
+			p.failed = failed
+			p.isLoading = false
+			p.redraw()
+		})
+	}()
+}
+
+func (p *InboxPage) title() string {
+	title := fmt.Sprintf("Review requests (%d)", len(p.rows))
+	if p.failed > 0 {
+		title += fmt.Sprintf(" [red]%d repositories failed, see the logs[-]", p.failed)
+	}
+
+	return title + " (enter open, R reload, esc close)"
+}
+
+func (p *InboxPage) redraw() {
+	p.Clear()
+	p.SetTitle(p.title())
+
+	for i, h := range inboxHeaders {
+		p.SetCell(0, i, tview.NewTableCell(h).
+			SetStyle(tcell.StyleDefault.Bold(true)).
+			SetSelectable(false))
+	}
+
+	if p.isLoading {
+		p.SetCell(1, 0, tview.NewTableCell("Loading...").SetSelectable(false))
+		return
+	}
+
+	if len(p.rows) == 0 {
+		p.SetCell(1, 0, tview.NewTableCell("No pull requests waiting for your review").SetSelectable(false))
+		return
+	}
+
+	now := time.Now()
+	for i, row := range p.rows {
+		pr := row.pullRequest
+		values := []string{
+			utils.FormatAge(now.Sub(pr.Created)),
+			row.data.Repository.Name,
+			pr.ID,
+			escapeString(cropString(pr.Title, 70)),
+			pr.User,
+		}
+
+		for j, v := range values {
+			p.SetCell(i+1, j, tview.NewTableCell(v))
+		}
+	}
+}
+
+func (p *InboxPage) selectedRow() *inboxRow {
+	index, _ := p.GetSelection()
+	if index < 1 || index > len(p.rows) {
+		return nil
+	}
+
+	return p.rows[index-1]
+}
+
+// GetSelectedPullRequest returns the selected pull request, the one of the
+// main table when it is already loaded there
+func (p *InboxPage) GetSelectedPullRequest() *PullRequest {
+	row := p.selectedRow()
+	if row == nil {
+		return nil
+	}
+
+	data, ok := state.RepositoryData[repoId(row.data.Repository)]
+	if !ok {
+		return nil
+	}
+
+	if pr, ok := data.PullRequests[row.pullRequest.ID]; ok {
+		return pr
+	}
+
+	return &PullRequest{
+		PullRequest: row.pullRequest,
+		Visible:     true,
+		Client:      row.data.Client,
+		Repository:  row.data.Repository,
+		GitUtil:     data.GitUtil,
+	}
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"preq/internal/logutils"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog"
+)
+
+type LogPage struct {
+	*tview.TextView
+	visible atomic.Bool
+}
+
+var logLevelColors = map[zerolog.Level]string{
+	zerolog.TraceLevel: "gray",
+	zerolog.DebugLevel: "gray",
+	zerolog.InfoLevel:  "green",
+	zerolog.WarnLevel:  "yellow",
+	zerolog.ErrorLevel: "red",
+	zerolog.FatalLevel: "red",
+	zerolog.PanicLevel: "red",
+}
+
+func formatLogEntry(e *logutils.Entry) string {
+	color, ok := logLevelColors[e.Level]
+	if !ok {
+		color = "white"
+	}
+
+	message := tview.Escape(e.Message)
+	if e.Error != "" {
+		message = fmt.Sprintf("%s: %s", message, tview.Escape(e.Error))
+	}
+
+	return fmt.Sprintf(
+		"[gray]%s[-] [%s]%-5s[-] %s",
+		e.Time.Format("15:04:05"),
+		color,
+		strings.ToUpper(e.Level.String()),
+		message,
+	)
+}
+
+// Refresh renders all the buffered log entries and scrolls to the latest
+func (p *LogPage) Refresh() {
+	lines := []string{}
+	for _, e := range logutils.Entries.Entries() {
+		lines = append(lines, formatLogEntry(e))
+	}
+
+	p.SetText(strings.Join(lines, "\n"))
+	p.ScrollToEnd()
+}
+
+func (p *LogPage) Open() {
+	p.visible.Store(true)
+	p.Refresh()
+}
+
+func (p *LogPage) Close() {
+	p.visible.Store(false)
+}
+
+func NewLogPage() *LogPage {
+	p := &LogPage{
+		TextView: tview.NewTextView().
+			SetDynamicColors(true).
+			SetScrollable(true).
+			SetWrap(true),
+	}
+
+	p.SetTitle("Logs (esc to close)").
+		SetBorder(true)
+
+	p.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Rune() == 'q' {
+			eventBus.Publish("LogPage:CloseRequested", nil)
+			return nil
+		}
+
+		return event
+	})
+
+	// Entries are logged from any goroutine, including the UI one, so the
+	// redraw is queued from a new goroutine to never block the logger
+	logutils.Entries.Subscribe(func(_ *logutils.Entry) {
+		if p.visible.Load() {
+			go app.QueueUpdateDraw(p.Refresh)
+		}
+	})
+
+	return p
+}
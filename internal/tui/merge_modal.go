@@ -5,16 +5,39 @@ import (
 	"preq/internal/pkg/client"
 
 	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
 )
 
+// mergeStrategyButtons maps the modal buttons to merge strategies,
+// the last button cancels the merge
+var mergeStrategyButtons = []struct {
+	Label    string
+	Strategy client.MergeStrategy
+}{
+	{"Merge", client.MergeStrategy_MERGE_COMMIT},
+	{"Squash", client.MergeStrategy_SQUASH},
+	{"Fast-forward", client.MergeStrategy_FAST_FORWARD},
+}
+
+func mergeModalButtons() []string {
+	buttons := []string{}
+	for _, b := range mergeStrategyButtons {
+		buttons = append(buttons, b.Label)
+	}
+
+	return append(buttons, "Cancel")
+}
+
 var mergeConfirmationModal = tview.NewModal().
 	SetText("Are you sure you want to merge %d pull requests?").
-	AddButtons([]string{"Merge", "Cancel"}).
+	AddButtons(mergeModalButtons()).
 	SetDoneFunc(mergeConfirmationCallback())
 
 func mergeConfirmationCallback() func(int, string) {
 	return func(buttonIndex int, buttonLabel string) {
-		if buttonIndex == 0 {
+		if buttonIndex >= 0 && buttonIndex < len(mergeStrategyButtons) {
+			strategy := mergeStrategyButtons[buttonIndex].Strategy
+
 			selectedPRs := make(map[string]*promptPullRequest)
 
 			for _, row := range table.GetSelectedRows() {
@@ -35,13 +58,16 @@ func mergeConfirmationCallback() func(int, string) {
 
 			go processPullRequestMap(
 				selectedPRs,
-				mergePR,
+				mergePR(strategy),
 				func(msg *utils.ProcessPullRequestResponse) {
-					if msg.Status == "Done" {
-						v := table.GetRowByGlobalID(msg.GlobalID)
-						// TODO: return an error instead?
-						if v != nil {
+					v := table.GetRowByGlobalID(msg.GlobalID)
+					// TODO: return an error instead?
+					if v != nil {
+						if msg.Status == "Done" {
 							v.PullRequest.State = client.PullRequestState_MERGED
+						} else {
+							log.Error().Err(msg.Error).Msgf("failed to merge pull request %s", msg.ID)
+							v.PullRequest.State = client.PullRequestState_OPEN
 						}
 					}
 
@@ -54,27 +80,36 @@ func mergeConfirmationCallback() func(int, string) {
 	}
 }
 
-func mergePR(
+func mergePR(strategy client.MergeStrategy) func(
 	cl client.Client,
 	r *client.Repository,
 	id string,
 	globalId string,
 	ch chan *utils.ProcessPullRequestResponse,
 ) {
-	_, err := cl.Merge(&client.MergeOptions{
-		Repository: r,
-		ID:         id,
-	})
-
-	res := &utils.ProcessPullRequestResponse{
-		ID:       id,
-		GlobalID: globalId,
-		Status:   "Done",
-	}
-	if err != nil {
-		res.Status = "Error"
-		res.Error = err
-	}
+	return func(
+		cl client.Client,
+		r *client.Repository,
+		id string,
+		globalId string,
+		ch chan *utils.ProcessPullRequestResponse,
+	) {
+		_, err := cl.Merge(&client.MergeOptions{
+			Repository: r,
+			ID:         id,
+			Strategy:   strategy,
+		})
+
+		res := &utils.ProcessPullRequestResponse{
+			ID:       id,
+			GlobalID: globalId,
+			Status:   "Done",
+		}
+		if err != nil {
+			res.Status = "Error"
+			res.Error = err
+		}
 
-	ch <- res
+		ch <- res
+	}
 }
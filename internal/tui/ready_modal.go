@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"preq/internal/cli/utils"
+	"preq/internal/pkg/client"
+
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+)
+
+var readyConfirmationModal = tview.NewModal().
+	SetText("Are you sure you want to mark %d pull requests as ready for review?").
+	AddButtons([]string{"Ready", "Cancel"}).
+	SetDoneFunc(readyConfirmationCallback)
+
+func readyConfirmationCallback(buttonIndex int, buttonLabel string) {
+	if buttonIndex == 0 {
+		selectedPRs := make(map[string]*promptPullRequest)
+
+		for _, row := range table.GetSelectedRows() {
+			row.Selected = false
+			if !row.PullRequest.IsDraft {
+				continue
+			}
+
+			selectedPRs[row.PullRequest.URL] = &promptPullRequest{
+				ID:         row.PullRequest.ID,
+				GlobalID:   row.PullRequest.URL,
+				Title:      row.PullRequest.Title,
+				Client:     row.Client,
+				Repository: row.Repository,
+			}
+		}
+
+		redraw()
+
+		go processPullRequestMap(
+			selectedPRs,
+			readyPR,
+			func(msg *utils.ProcessPullRequestResponse) {
+				v := table.GetRowByGlobalID(msg.GlobalID)
+				if v == nil {
+					return
+				}
+
+				if msg.Status == "Done" {
+					v.PullRequest.IsDraft = false
+				} else {
+					log.Error().Err(msg.Error).Msgf("failed to mark pull request %s as ready", msg.ID)
+				}
+
+				app.QueueUpdateDraw(redraw)
+			},
+		)
+	}
+
+	eventBus.Publish("readyModal:closed", nil)
+}
+
+func readyPR(
+	cl client.Client,
+	r *client.Repository,
+	id string,
+	globalId string,
+	c chan *utils.ProcessPullRequestResponse,
+) {
+	draft := false
+	_, err := cl.UpdatePullRequest(&client.UpdatePullRequestOptions{
+		Repository: r,
+		ID:         id,
+		Draft:      &draft,
+	})
+
+	res := &utils.ProcessPullRequestResponse{
+		ID:       id,
+		GlobalID: globalId,
+		Status:   "Done",
+	}
+	if err != nil {
+		res.Status = "Error"
+		res.Error = err
+	}
+
+	c <- res
+}
@@ -427,8 +427,9 @@ func (ct *ReviewPanel) prerenderContent(diffId string) {
 
 			color := "white"
 			oldLineNumber := fmt.Sprint(origIdx)
-			diffLineType := DiffLineTypeAdded
+			diffLineType := DiffLineTypeContext
 			if isAddedLine {
+				diffLineType = DiffLineTypeAdded
 				oldLineNumber = ""
 				color = "green"
 			}
@@ -440,16 +441,22 @@ func (ct *ReviewPanel) prerenderContent(diffId string) {
 				color = "red"
 			}
 
-			lineNumber := origIdx
-			if isAddedLine {
-				lineNumber = newIdx
+			lineNumber := newIdx
+			if isRemoveLine {
+				lineNumber = origIdx
+			}
+
+			originalLineNumber := 0
+			if isCommonLine {
+				originalLineNumber = int(origIdx)
 			}
 
 			ct.content = append(ct.content, &ScrollablePageLine{
 				Reference: &diffLine{
-					FilePath:   d.DiffId,
-					LineNumber: int(lineNumber),
-					Type:       diffLineType,
+					FilePath:           d.DiffId,
+					LineNumber:         int(lineNumber),
+					Type:               diffLineType,
+					OriginalLineNumber: originalLineNumber,
 				},
 				Statements: []*ScrollablePageLineStatement{
 					{Content: fmt.Sprintf(
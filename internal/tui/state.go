@@ -19,10 +19,16 @@ type PullRequest struct {
 }
 
 type RepositoryData struct {
-	Name         string
-	IsLoading    bool
+	Name          string
+	IsLoading     bool
+	IsLoadingMore bool
+	// TotalCount is the number of pull requests reported by the
+	// provider, zero when the provider does not report it
+	TotalCount   int
 	PullRequests map[string]*PullRequest
 	GitUtil      gitutils.GitUtilsClient
+	// CurrentUser is the authenticated user, nil until it is resolved
+	CurrentUser *client.User
 }
 
 type tuiState struct {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
 )
 
 func escapeString(s string) string {
@@ -172,7 +173,16 @@ func (prt *pullRequestTable) loadPRs(app *tview.Application) {
 	for _, data := range prt.tableData {
 		id := repoId(data.Repository)
 
-		utilClient, _ := gitutils.GetRepo(data.Path)
+		// The details pages use the provider's diff without a local clone
+		var utilClient gitutils.GitUtilsClient
+		if data.Path != "" {
+			c, err := gitutils.GetRepo(data.Path)
+			if err != nil {
+				log.Error().Err(err).Msgf("failed to open the local clone of %s", data.Repository.Name)
+			} else {
+				utilClient = c
+			}
+		}
 
 		if _, ok := state.RepositoryData[id]; !ok {
 			state.RepositoryData[id] = &RepositoryData{
@@ -184,9 +194,24 @@ func (prt *pullRequestTable) loadPRs(app *tview.Application) {
 		}
 
 		go prt.loadPR(app, data)
+		go prt.loadCurrentUser(app, data)
 	}
 }
 
+func (prt *pullRequestTable) loadCurrentUser(app *tview.Application, data *tableRepoData) {
+	user, err := data.Client.GetCurrentUser()
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to resolve the current user of %s", data.Repository.Name)
+		return
+	}
+
+	id := repoId(data.Repository)
+	app.QueueUpdateDraw(func() {
+		state.RepositoryData[id].CurrentUser = user
+		prt.redraw()
+	})
+}
+
 func repoId(repo *client.Repository) string {
 	return fmt.Sprintf(
 		"%v___%v",
@@ -195,10 +220,45 @@ func repoId(repo *client.Repository) string {
 	)
 }
 
+// miscInfoConcurrency limits the number of pull requests whose reviews are
+// requested at once across all the repositories, the providers rate limit
+// the concurrent requests
+const miscInfoConcurrency = 8
+
+var miscInfoSemaphore = make(chan struct{}, miscInfoConcurrency)
+
+// loadMiscInfo loads the reviews and the comment count of the pull request,
+// the loading indicators are cleared also when it fails
+func (prt *pullRequestTable) loadMiscInfo(
+	app *tview.Application,
+	data *tableRepoData,
+	v *client.PullRequest,
+) {
+	miscInfoSemaphore <- struct{}{}
+	err := data.Client.FillMiscInfoAsync(data.Repository, v)
+	<-miscInfoSemaphore
+	if err != nil {
+		log.Error().Err(err).Msgf("failed to load the reviews of %s #%s", data.Repository.Name, v.ID)
+	}
+
+	app.QueueUpdateDraw(func() {
+		pr, ok := state.RepositoryData[repoId(data.Repository)].PullRequests[v.ID]
+		if !ok {
+			return
+		}
+
+		pr.IsApprovalsLoading = false
+		pr.IsCommentsLoading = false
+		pr.IsChangesRequestsLoading = false
+		prt.redraw()
+	})
+}
+
 func (prt *pullRequestTable) loadPR(app *tview.Application, data *tableRepoData) {
 	// TODO: This load should be in table write code
 	// TODO here just the state should be updater
 
+	id := repoId(data.Repository)
 	nextURL := ""
 	for {
 		prs, err := data.Client.GetPullRequests(&client.GetPullRequestsOptions{
@@ -207,6 +267,17 @@ func (prt *pullRequestTable) loadPR(app *tview.Application, data *tableRepoData)
 			Next:       nextURL,
 		})
 		if err != nil {
+			log.Error().Err(err).Msgf("failed to load pull requests of %s", data.Repository.Name)
+
+			// Keep the already loaded pages when a subsequent page fails
+			if nextURL != "" {
+				state.RepositoryData[id].IsLoadingMore = false
+				app.QueueUpdateDraw(func() {
+					prt.redraw()
+				})
+				return
+			}
+
 			app.QueueUpdateDraw(func() {
 				prt.SetCell(0, 0,
 					tview.
@@ -217,7 +288,6 @@ func (prt *pullRequestTable) loadPR(app *tview.Application, data *tableRepoData)
 			return
 		}
 
-		id := repoId(data.Repository)
 		for _, v := range prs.Values {
 			data.Values = append(data.Values, &pullRequestTableRow{
 				pullRequest: v,
@@ -239,35 +309,32 @@ func (prt *pullRequestTable) loadPR(app *tview.Application, data *tableRepoData)
 				GitUtil:                  state.RepositoryData[id].GitUtil,
 			}
 
-			go func(v *client.PullRequest) {
-				err := data.Client.FillMiscInfoAsync(
-					data.Repository,
-					v,
-				)
-				if err != nil {
-					return
-				}
-
-				id := repoId(data.Repository)
-				pr := state.RepositoryData[id].PullRequests[v.ID]
-				pr.IsApprovalsLoading = false
-				pr.IsCommentsLoading = false
-				pr.IsChangesRequestsLoading = false
-
-				app.QueueUpdateDraw(func() {
-					prt.redraw()
-				})
-			}(v)
+			go prt.loadMiscInfo(app, data, v)
 		}
 
+		nextURL = prs.NextURL
+
 		state.RepositoryData[id].IsLoading = false
+		state.RepositoryData[id].IsLoadingMore = nextURL != ""
+		state.RepositoryData[id].TotalCount = int(prs.Size)
 		app.QueueUpdateDraw(func() {
 			prt.redraw()
 		})
 
-		// TODO: Only the first page of pull requests is fetched
-		break
+		if nextURL == "" {
+			break
+		}
+	}
+}
+
+func isApprovedBy(pr *client.PullRequest, user *client.User) bool {
+	for _, a := range pr.Approvals {
+		if user.Matches(a.User) {
+			return true
+		}
 	}
+
+	return false
 }
 
 func addEmptyRow(prt *pullRequestTable, offset int) {
@@ -324,6 +391,9 @@ func (prt *pullRequestTable) drawTable() {
 		// prt.setRowSelectable(offset, false)
 		prt.GetCell(offset, 0).SetText("REPO")
 		prt.GetCell(offset, 5).SetText(data.Name)
+		if !data.IsLoading {
+			prt.GetCell(offset, 6).SetText(repositoryCountText(data))
+		}
 
 		offset += 1
 
@@ -379,11 +449,26 @@ func (prt *pullRequestTable) drawTable() {
 					prt.colorRow(offset, tview.Styles.PrimaryTextColor)
 				}
 
+				if pr.PullRequest.IsDraft {
+					prt.GetCell(offset, 1).
+						SetText(IconsMap["Draft"]).
+						SetTextColor(tcell.ColorGray)
+				}
+
+				if data.CurrentUser.Matches(pr.PullRequest.User) {
+					prt.GetCell(offset, 6).SetText(
+						fmt.Sprintf("%s %s", IconsMap["MyPullRequest"], pr.PullRequest.User),
+					)
+				}
+
 				approvalsText := ""
 				if pr.IsApprovalsLoading {
 					approvalsText = IconsMap["Working"]
 				} else if len(pr.PullRequest.Approvals) > 0 {
 					approvalsText = fmt.Sprintf("[%s::]%d[-::]", "green", len(pr.PullRequest.Approvals))
+					if isApprovedBy(pr.PullRequest, data.CurrentUser) {
+						approvalsText += IconsMap["MyApproval"]
+					}
 				}
 				prt.GetCell(offset, 2).SetText(approvalsText)
 
@@ -407,12 +492,28 @@ func (prt *pullRequestTable) drawTable() {
 			}
 		}
 
+		if data.IsLoadingMore {
+			addEmptyRow(prt, offset)
+			prt.SetCell(offset, 0, tview.NewTableCell("Loading more..."))
+			prt.setRowSelectable(offset, false)
+			offset++
+		}
+
 		addEmptyRow(prt, offset)
 		prt.setRowSelectable(offset, false)
 		offset++
 	}
 }
 
+func repositoryCountText(data *RepositoryData) string {
+	count := len(data.PullRequests)
+	if data.TotalCount > count {
+		return fmt.Sprintf("%d of %d", count, data.TotalCount)
+	}
+
+	return fmt.Sprint(count)
+}
+
 func (prt *pullRequestTable) updateRowStatus(
 	rowId int,
 	text string,
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -34,6 +35,7 @@ const (
 	PAGE_UNAPPROVE_CONFIRMATION_MODAL = "aage_unapprove_confirmation_modal"
 	PAGE_MERGE_CONFIRMATION_MODAL     = "page_merge_confirmation_modal"
 	PAGE_DECLINE_CONFIRMATION_MODAL   = "confirmation_modal"
+	PAGE_READY_CONFIRMATION_MODAL     = "page_ready_confirmation_modal"
 )
 
 func loadDefaultConfig() (*viper.Viper, error) {
@@ -60,7 +62,7 @@ func loadConfig(
 	}
 
 	c, err := clientutils.ClientFactory{}.NewClient(
-		client.RepositoryProviderEnum.BITBUCKET,
+		client.RepositoryProvider(repoInfo.Provider),
 		config,
 	)
 	if err != nil {
@@ -86,7 +88,7 @@ func Run(
 	params *paramutils.RepositoryParams,
 	repos []*persistance.PersistanceRepoInfo,
 ) {
-	loadDefaultConfig()
+	config, _ := loadDefaultConfig()
 	// app.SetScreen(tcell.NewSimulationScreen("sim"))
 
 	// tview.Styles.TitleColor = tcell.ColorDarkOrange
@@ -104,6 +106,7 @@ func Run(
 		details = newDetailsPage()
 	)
 	table = NewPullRequestTable()
+	details.diffOptions = initDiffOptions(config)
 
 	// tview.Borders.TopLeft = '╭'
 	// tview.Borders.TopRight = '╮'
@@ -159,25 +162,30 @@ func Run(
 		app.SetFocus(table)
 	})
 
-	eventBus.Subscribe("detailsPage:open", func(input interface{}) {
-		pr, ok := input.(*PullRequest)
-		if !ok {
-			err := errors.New("cast failed when opening the details page")
-			log.Error().Msg(err.Error())
-			return
-		}
+	gitFetchModal := NewGitFetchModal()
 
+	// openDetails shows the details page of the pull request, the missing
+	// commits are fetched first when fetch is set
+	openDetails := func(pr *PullRequest, fetch bool) {
 		err := details.SetData(pr)
 		if err != nil {
-			if errors.Is(err, gitutils.ErrCommitHashNotFound) {
+			if fetch && errors.Is(err, gitutils.ErrCommitHashNotFound) {
 				info, err := persistance.GetDefault().GetInfo(pr.Repository.Name, string(pr.Repository.Provider))
 				if err != nil {
-					fmt.Println(err.Error())
+					log.Error().Err(err).Msg("failed to find the local repository")
+					eventBus.Publish("ErrorModal:RequestOpen", err)
+					return
+				}
+				if info.Path == "" {
+					eventBus.Publish("ErrorModal:RequestOpen", fmt.Errorf(
+						"the commits of pull request #%s are missing and no local clone of %s is known",
+						pr.PullRequest.ID,
+						pr.Repository.Name,
+					))
 					return
 				}
 
-				go gitFetchModal.StartGitFetch(info.Path)
-
+				gitFetchModal.StartGitFetch(info.Path, pr)
 				eventBus.Publish("GitFetchModal:RequestOpen", nil)
 			} else {
 				log.Error().Msg(err.Error())
@@ -189,16 +197,36 @@ func Run(
 
 		pages.ShowPage("details_page")
 		app.SetFocus(details)
+	}
+
+	eventBus.Subscribe("detailsPage:open", func(input interface{}) {
+		pr, ok := input.(*PullRequest)
+		if !ok {
+			err := errors.New("cast failed when opening the details page")
+			log.Error().Msg(err.Error())
+			return
+		}
+
+		openDetails(pr, true)
 	})
 
-	eventBus.Subscribe("GitFetchModal:RequestOpen", func(err interface{}) {
+	eventBus.Subscribe("GitFetchModal:RequestOpen", func(_ interface{}) {
 		pages.ShowPage("GitFetchModal")
+		app.SetFocus(gitFetchModal)
 	})
 
-	eventBus.Subscribe("GitFetchModal:RequestClose", func(err interface{}) {
-		pages.SwitchToPage("main")
+	eventBus.Subscribe("GitFetchModal:RequestClose", func(_ interface{}) {
+		pages.HidePage("GitFetchModal")
 		app.SetFocus(table)
-		app.Draw()
+	})
+
+	// The fetched commits are not fetched again when they are still missing,
+	// the error is shown instead
+	eventBus.Subscribe("GitFetchModal:Fetched", func(input interface{}) {
+		pages.HidePage("GitFetchModal")
+		if pr, ok := input.(*PullRequest); ok {
+			openDetails(pr, false)
+		}
 	})
 
 	eventBus.Subscribe("ErrorModal:RequestOpen", func(err interface{}) {
@@ -231,6 +259,11 @@ func Run(
 		app.SetFocus(table)
 	})
 
+	eventBus.Subscribe("readyModal:closed", func(_ interface{}) {
+		pages.SwitchToPage("main")
+		app.SetFocus(table)
+	})
+
 	eventBus.Subscribe("BrowserUrlOpen", func(data interface{}) {
 		url := data.(string)
 		var err error
@@ -254,7 +287,7 @@ func Run(
 	grid := tview.NewGrid().
 		SetRows(0, 1).
 		AddItem(table, 0, 0, 1, 1, 0, 0, false).
-		AddItem(tview.NewTextView().SetScrollable(true).SetText("Help: / filter ctrl+u unapprove j/k up/down"), 1, 0, 1, 1, 0, 0, false)
+		AddItem(tview.NewTextView().SetScrollable(true).SetText("Help: / filter i inbox c checkout w worktree e edit r ready ctrl+u unapprove L logs j/k up/down"), 1, 0, 1, 1, 0, 0, false)
 
 	grid.
 		SetBorders(false).
@@ -339,6 +372,49 @@ func Run(
 				eventBus.Publish("detailsPage:open", pr)
 			}
 			return nil
+		case 'e':
+			pr, err := table.GetSelectedPullRequest()
+			if err == nil && pr != nil {
+				eventBus.Publish("EditModal:OpenRequested", pr)
+			}
+			return nil
+		case 'r':
+			count := 0
+			for _, row := range table.GetSelectedRows() {
+				if row.PullRequest.IsDraft {
+					count++
+				}
+			}
+
+			if count > 0 {
+				readyConfirmationModal.
+					SetText(
+						fmt.Sprintf(
+							"Are you sure you want to mark %v pull requests as ready for review?",
+							count,
+						),
+					)
+				pages.ShowPage(PAGE_READY_CONFIRMATION_MODAL)
+			}
+			return nil
+		case 'L':
+			eventBus.Publish("LogPage:OpenRequested", nil)
+			return nil
+		case 'i':
+			eventBus.Publish("InboxPage:OpenRequested", nil)
+			return nil
+		case 'c':
+			pr, err := table.GetSelectedPullRequest()
+			if err == nil && pr != nil {
+				eventBus.Publish("CheckoutModal:OpenRequested", pr)
+			}
+			return nil
+		case 'w':
+			pr, err := table.GetSelectedPullRequest()
+			if err == nil && pr != nil {
+				eventBus.Publish("WorktreeModal:OpenRequested", pr)
+			}
+			return nil
 		case 'q':
 			app.Stop()
 			return nil
@@ -392,8 +468,14 @@ func Run(
 		false,
 		false,
 	)
+	pages.AddPage(
+		PAGE_READY_CONFIRMATION_MODAL,
+		readyConfirmationModal,
+		false,
+		false,
+	)
 
-	pages.AddPage("GitFetchModal", gitFetchModal, true, false)
+	pages.AddPage("GitFetchModal", gitFetchModal, false, false)
 	pages.AddPage("FatalErrorModal", fatalErrorModal, false, false)
 	pages.AddPage("ErrorModal", errorModal, false, false)
 
@@ -429,6 +511,22 @@ func Run(
 		eventBus.Publish("AddCommentModal:Closed", nil)
 	})
 
+	editModal := NewEditModal()
+	pages.AddPage("EditModal", editModal, true, false)
+
+	eventBus.Subscribe("EditModal:OpenRequested", func(input interface{}) {
+		if pr, ok := input.(*PullRequest); ok {
+			editModal.SetData(pr)
+			pages.ShowPage("EditModal")
+			app.SetFocus(editModal)
+		}
+	})
+
+	eventBus.Subscribe("EditModal:CloseRequested", func(_ interface{}) {
+		pages.HidePage("EditModal")
+		app.SetFocus(table)
+	})
+
 	eventBus.Subscribe("FilterModal:OpenRequested", func(input interface{}) {
 		if filterData, ok := input.([]*FilterModalItem); ok {
 			filterModal.Clear()
@@ -453,6 +551,67 @@ func Run(
 		eventBus.Publish("FilterModal:Closed", nil)
 	})
 
+	logPage := NewLogPage()
+	pages.AddPage("LogPage", logPage, true, false)
+
+	eventBus.Subscribe("LogPage:OpenRequested", func(_ interface{}) {
+		logPage.Open()
+		pages.ShowPage("LogPage")
+		app.SetFocus(logPage)
+	})
+
+	eventBus.Subscribe("LogPage:CloseRequested", func(_ interface{}) {
+		logPage.Close()
+		pages.HidePage("LogPage")
+		app.SetFocus(table)
+	})
+
+	checkoutModal := NewCheckoutModal()
+	pages.AddPage("CheckoutModal", checkoutModal, false, false)
+
+	eventBus.Subscribe("CheckoutModal:OpenRequested", func(input interface{}) {
+		if pr, ok := input.(*PullRequest); ok {
+			checkoutModal.Start(pr, false)
+			pages.ShowPage("CheckoutModal")
+			app.SetFocus(checkoutModal)
+		}
+	})
+
+	eventBus.Subscribe("CheckoutModal:CloseRequested", func(_ interface{}) {
+		pages.HidePage("CheckoutModal")
+		app.SetFocus(table)
+	})
+
+	worktreeModal := NewWorktreeModal()
+	pages.AddPage("WorktreeModal", worktreeModal, false, false)
+
+	eventBus.Subscribe("WorktreeModal:OpenRequested", func(input interface{}) {
+		if pr, ok := input.(*PullRequest); ok {
+			worktreeModal.Start(pr)
+			pages.ShowPage("WorktreeModal")
+			app.SetFocus(worktreeModal)
+		}
+	})
+
+	eventBus.Subscribe("WorktreeModal:CloseRequested", func(_ interface{}) {
+		pages.HidePage("WorktreeModal")
+		app.SetFocus(table)
+	})
+
+	inboxPage := NewInboxPage()
+	pages.AddPage("InboxPage", inboxPage, true, false)
+
+	eventBus.Subscribe("InboxPage:OpenRequested", func(_ interface{}) {
+		inboxPage.Load(tableData)
+		pages.ShowPage("InboxPage")
+		app.SetFocus(inboxPage)
+	})
+
+	eventBus.Subscribe("InboxPage:CloseRequested", func(_ interface{}) {
+		pages.HidePage("InboxPage")
+		app.SetFocus(table)
+	})
+
 	tableData = make([]*tableRepoData, 0)
 	for _, v := range repos {
 		c, repo, err := loadConfig(&persistance.PersistanceRepoInfo{
@@ -477,6 +636,13 @@ func Run(
 		app.QueueUpdateDraw(redraw)
 	}()
 
+	// The background fetch is stopped when the application exits
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if config != nil && config.GetBool("general.fetchOnStartup") {
+		go fetchRepositories(ctx, tableData)
+	}
+
 	app.SetRoot(pages, true) //.EnableMouse(true)
 	app.SetFocus(table)
 
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"preq/internal/configutils"
+	"preq/internal/editorutils"
+	"preq/internal/gitutils"
+	"preq/internal/persistance"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+)
+
+// WorktreeModal creates the review worktree of the pull request and opens a
+// shell or the editor in it
+type WorktreeModal struct {
+	*tview.Modal
+	path string
+}
+
+func NewWorktreeModal() *WorktreeModal {
+	m := &WorktreeModal{Modal: tview.NewModal()}
+
+	m.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		switch buttonLabel {
+		case "Shell":
+			shell := strings.TrimSpace(os.Getenv("SHELL"))
+			if shell == "" {
+				shell = "sh"
+			}
+			m.run(shell)
+		case "Editor":
+			// The editor may have arguments, e.g. "code --wait"
+			m.run(append(strings.Fields(editorutils.GetEditor()), ".")...)
+		default:
+			eventBus.Publish("WorktreeModal:CloseRequested", nil)
+		}
+	})
+
+	return m
+}
+
+func (m *WorktreeModal) setState(text string, buttons ...string) {
+	m.ClearButtons().
+		SetText(text).
+		AddButtons(buttons)
+}
+
+// Start creates or updates the worktree in the background
+func (m *WorktreeModal) Start(pr *PullRequest) {
+	m.path = ""
+	m.setState(fmt.Sprintf("Preparing the worktree of pull request #%s...", pr.PullRequest.ID))
+
+	go func() {
+		path, err := addPullRequestWorktree(pr)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				log.Error().Err(err).Msgf("failed to create the worktree of pull request #%s", pr.PullRequest.ID)
+				m.setState(fmt.Sprintf("Creating the worktree failed: %s", err), "Close")
+				return
+			}
+
+			m.path = path
+			m.setState(fmt.Sprintf("The worktree is ready at %s", path), "Shell", "Editor", "Close")
+		})
+	}()
+}
+
+// run suspends the application while the command runs in the worktree
+func (m *WorktreeModal) run(args ...string) {
+	app.Suspend(func() {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = m.path
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		err := cmd.Run()
+		if err != nil {
+			log.Error().Err(err).Msgf("failed to run %s in %s", args[0], m.path)
+		}
+	})
+}
+
+func addPullRequestWorktree(pr *PullRequest) (string, error) {
+	info, err := persistance.GetDefault().GetInfo(
+		pr.Repository.Name,
+		string(pr.Repository.Provider),
+	)
+	if err != nil {
+		return "", err
+	}
+	if info.Path == "" {
+		return "", fmt.Errorf("no local clone of %s is known", pr.Repository.Name)
+	}
+
+	config, err := configutils.LoadConfigForPath(info.Path)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := configutils.WorktreeDir(config)
+	if err != nil {
+		return "", err
+	}
+
+	path := gitutils.WorktreePath(dir, pr.Repository, pr.PullRequest.ID)
+	err = gitutils.AddPullRequestWorktree(info.Path, path, pr.Repository, pr.PullRequest)
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}